@@ -159,6 +159,16 @@ func newRootCmd() *ffcli.Command {
 		return nil
 	})
 	rootfs.Lookup("socket").DefValue = localClient.Socket
+	colorMode = "auto"
+	rootfs.Func("color", `when to use colored output: "auto", "always", or "never"`, func(s string) error {
+		switch s {
+		case "auto", "always", "never":
+			colorMode = s
+			return nil
+		}
+		return fmt.Errorf("invalid --color value %q; must be auto, always, or never", s)
+	})
+	rootfs.Lookup("color").DefValue = colorMode
 
 	rootCmd := &ffcli.Command{
 		Name:       "tailscale",
@@ -179,6 +189,7 @@ func newRootCmd() *ffcli.Command {
 			switchCmd,
 			configureCmd,
 			netcheckCmd,
+			doctorCmd,
 			ipCmd,
 			statusCmd,
 			pingCmd,
@@ -388,16 +399,34 @@ func countFlags(fs *flag.FlagSet) (n int) {
 	return n
 }
 
-// colorableOutput returns a colorable writer if stdout is a terminal (not, say,
-// redirected to a file or pipe), the Stdout writer is os.Stdout (we're not
-// embedding the CLI in wasm or a mobile app), and NO_COLOR is not set (see
-// https://no-color.org/). If any of those is not the case, ok is false
-// and w is Stdout.
+// colorMode is the value of the root --color flag: "auto", "always", or
+// "never".
+var colorMode = "auto"
+
+// colorableOutput returns a colorable writer if colored output is wanted, as
+// decided by useColor. If it's not, ok is false and w is Stdout.
 func colorableOutput() (w io.Writer, ok bool) {
-	if Stdout != os.Stdout ||
-		os.Getenv("NO_COLOR") != "" ||
-		!isatty.IsTerminal(os.Stdout.Fd()) {
+	isStdout := Stdout == os.Stdout
+	if !useColor(colorMode, os.Getenv("NO_COLOR") != "", isStdout && isatty.IsTerminal(os.Stdout.Fd())) {
 		return Stdout, false
 	}
+	if !isStdout {
+		return Stdout, true
+	}
 	return colorable.NewColorableStdout(), true
 }
+
+// useColor reports whether to use colored output. An explicit --color=always
+// or --color=never wins; otherwise (--color=auto), color is used only if
+// NO_COLOR is not set (see https://no-color.org/) and the output is a
+// terminal. Output to anything other than os.Stdout (such as when embedding
+// the CLI in wasm or a mobile app) is never considered a terminal.
+func useColor(mode string, noColor, isTerminal bool) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return !noColor && isTerminal
+}
@@ -6,26 +6,35 @@
 import (
 	"bytes"
 	stdcmp "cmp"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/netip"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 	"github.com/google/go-cmp/cmp"
+	"tailscale.com/client/tailscale"
 	"tailscale.com/envknob"
 	"tailscale.com/health/healthmsg"
 	"tailscale.com/ipn"
 	"tailscale.com/ipn/ipnstate"
+	"tailscale.com/paths"
 	"tailscale.com/tailcfg"
 	"tailscale.com/tka"
 	"tailscale.com/tstest"
 	"tailscale.com/types/logger"
 	"tailscale.com/types/persist"
 	"tailscale.com/types/preftype"
+	"tailscale.com/types/ptr"
 	"tailscale.com/version/distro"
 )
 
@@ -1410,6 +1419,165 @@ func TestUpWorthWarning(t *testing.T) {
 	}
 }
 
+// fakeUpLocalAPI is a fake LocalAPI server for the requests made by
+// "tailscale up".
+type fakeUpLocalAPI struct {
+	statuses []*ipnstate.Status // returned by successive status requests; the last one repeats
+	notifies []ipn.Notify       // sent on the IPN bus
+	startErr string             // if non-empty, start requests fail with this error
+
+	mu sync.Mutex
+}
+
+func (f *fakeUpLocalAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/localapi/v0/status":
+		f.mu.Lock()
+		st := f.statuses[0]
+		if len(f.statuses) > 1 {
+			f.statuses = f.statuses[1:]
+		}
+		f.mu.Unlock()
+		json.NewEncoder(w).Encode(st)
+	case "/localapi/v0/prefs":
+		prefs := ipn.NewPrefs()
+		prefs.ControlURL = ipn.DefaultControlURL
+		json.NewEncoder(w).Encode(prefs)
+	case "/localapi/v0/check-prefs":
+		w.WriteHeader(http.StatusOK)
+	case "/localapi/v0/start":
+		if f.startErr != "" {
+			http.Error(w, f.startErr, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case "/localapi/v0/login-interactive":
+		w.WriteHeader(http.StatusNoContent)
+	case "/localapi/v0/watch-ipn-bus":
+		enc := json.NewEncoder(w)
+		for _, n := range f.notifies {
+			enc.Encode(n)
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestUpJSONOutput(t *testing.T) {
+	const authURL = "https://login.tailscale.com/a/0123456789abcdef"
+	needsLogin := &ipnstate.Status{BackendState: ipn.NeedsLogin.String()}
+	running := &ipnstate.Status{BackendState: ipn.Running.String(), HaveNodeKey: true}
+	tests := []struct {
+		name    string
+		api     *fakeUpLocalAPI
+		wantErr string
+		want    []upOutputJSON
+	}{
+		{
+			name: "needs-login",
+			api: &fakeUpLocalAPI{
+				statuses: []*ipnstate.Status{needsLogin, {
+					BackendState: ipn.Running.String(),
+					HaveNodeKey:  true,
+					Health:       []string{healthmsg.WarnAcceptRoutesOff, "not in map poll"},
+				}},
+				notifies: []ipn.Notify{
+					{State: ptr.To(ipn.NeedsLogin)},
+					{BrowseToURL: ptr.To(authURL)},
+					{State: ptr.To(ipn.Running)},
+				},
+			},
+			want: []upOutputJSON{
+				{AuthURL: authURL, BackendState: "NeedsLogin"},
+				{AuthURL: authURL, LoggedIn: true, BackendState: "Running", Warnings: []string{healthmsg.WarnAcceptRoutesOff}},
+			},
+		},
+		{
+			name: "needs-machine-auth",
+			api: &fakeUpLocalAPI{
+				statuses: []*ipnstate.Status{needsLogin, running},
+				notifies: []ipn.Notify{
+					{BrowseToURL: ptr.To(authURL)},
+					{State: ptr.To(ipn.NeedsMachineAuth)},
+					{State: ptr.To(ipn.Running)},
+				},
+			},
+			want: []upOutputJSON{
+				{AuthURL: authURL, BackendState: "NeedsLogin"},
+				{BackendState: "NeedsMachineAuth"},
+				{AuthURL: authURL, LoggedIn: true, BackendState: "Running"},
+			},
+		},
+		{
+			name: "already-authenticated",
+			api:  &fakeUpLocalAPI{statuses: []*ipnstate.Status{running}},
+			want: []upOutputJSON{
+				{LoggedIn: true, BackendState: "Running"},
+			},
+		},
+		{
+			name: "error",
+			api: &fakeUpLocalAPI{
+				statuses: []*ipnstate.Status{needsLogin},
+				startErr: "control plane unreachable",
+			},
+			wantErr: "500 Internal Server Error: control plane unreachable",
+			want: []upOutputJSON{
+				{Error: "500 Internal Server Error: control plane unreachable"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.api)
+			defer srv.Close()
+			localClient = tailscale.LocalClient{
+				Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "tcp", srv.Listener.Addr().String())
+				},
+			}
+			var buf bytes.Buffer
+			oldStdout := Stdout
+			Stdout = &buf
+			defer func() {
+				Stdout = oldStdout
+				localClient = tailscale.LocalClient{Socket: paths.DefaultTailscaledSocket()}
+			}()
+
+			var upArgs upArgsT
+			if err := newUpFlagSet("linux", &upArgs, "up").Parse([]string{"--json"}); err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			err := runUp(ctx, "up", nil, upArgs)
+			if gotErr := fmt.Sprint(err); (err != nil || tt.wantErr != "") && gotErr != tt.wantErr {
+				t.Fatalf("runUp error = %v; want %q", err, tt.wantErr)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			var got []upOutputJSON
+			for _, line := range lines {
+				var js upOutputJSON
+				if err := json.Unmarshal([]byte(line), &js); err != nil {
+					t.Fatalf("line %q: %v", line, err)
+				}
+				if js.AuthURL != "" && js.BackendState == "NeedsLogin" && !strings.HasPrefix(js.QR, "data:image/png;base64,") {
+					t.Errorf("auth URL event without QR code: %q", line)
+				}
+				js.QR = ""
+				got = append(got, js)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestParseNLArgs(t *testing.T) {
 	tcs := []struct {
 		name              string
@@ -1477,3 +1645,47 @@ func TestParseNLArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestUseColor(t *testing.T) {
+	tests := []struct {
+		mode       string
+		noColor    bool
+		isTerminal bool
+		want       bool
+	}{
+		{"auto", false, true, true},
+		{"auto", false, false, false},
+		{"auto", true, true, false},
+		{"always", false, false, true},
+		{"always", true, false, true}, // --color beats NO_COLOR
+		{"never", false, true, false},
+		{"never", true, true, false},
+	}
+	for _, tt := range tests {
+		if got := useColor(tt.mode, tt.noColor, tt.isTerminal); got != tt.want {
+			t.Errorf("useColor(%q, noColor=%v, isTerminal=%v) = %v; want %v", tt.mode, tt.noColor, tt.isTerminal, got, tt.want)
+		}
+	}
+}
+
+func TestColorFlag(t *testing.T) {
+	for _, mode := range []string{"auto", "always", "never"} {
+		if err := newRootCmd().Parse([]string{"--color=" + mode}); err != nil {
+			t.Fatalf("--color=%s: %v", mode, err)
+		}
+		if colorMode != mode {
+			t.Errorf("--color=%s: colorMode = %q", mode, colorMode)
+		}
+	}
+
+	// A new root command starts from the default.
+	newRootCmd()
+	if colorMode != "auto" {
+		t.Errorf("default colorMode = %q; want auto", colorMode)
+	}
+
+	// Parse exits on invalid flag values, so set the flag directly.
+	if err := newRootCmd().FlagSet.Lookup("color").Value.Set("sometimes"); err == nil || !strings.Contains(err.Error(), "invalid --color value") {
+		t.Errorf("--color=sometimes: got error %v; want invalid value", err)
+	}
+}
@@ -21,7 +21,9 @@
 	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -40,6 +42,8 @@
 	"tailscale.com/tailcfg"
 	"tailscale.com/types/key"
 	"tailscale.com/types/logger"
+	"tailscale.com/types/netmap"
+	"tailscale.com/types/views"
 	"tailscale.com/util/must"
 	"tailscale.com/wgengine/capture"
 )
@@ -217,12 +221,13 @@
 		},
 		{
 			Name:       "netmap",
-			ShortUsage: "tailscale debug netmap",
+			ShortUsage: "tailscale debug netmap [--section=<name>[,<name>...]]",
 			Exec:       runNetmap,
 			ShortHelp:  "Print the current network map",
 			FlagSet: (func() *flag.FlagSet {
 				fs := newFlagSet("netmap")
 				fs.BoolVar(&netmapArgs.showPrivateKey, "show-private-key", false, "include node private key in printed netmap")
+				fs.StringVar(&netmapArgs.section, "section", "", "if non-empty, comma-separated sections of the netmap to print: "+strings.Join(netmapSectionNames, ", "))
 				return fs
 			})(),
 		},
@@ -276,12 +281,13 @@
 		},
 		{
 			Name:       "capture",
-			ShortUsage: "tailscale debug capture",
+			ShortUsage: "tailscale debug capture [-o <file>] [--duration=<duration>]",
 			Exec:       runCapture,
 			ShortHelp:  "Streams pcaps for debugging",
 			FlagSet: (func() *flag.FlagSet {
 				fs := newFlagSet("capture")
 				fs.StringVar(&captureArgs.outFile, "o", "", "path to stream the pcap (or - for stdout), leave empty to start wireshark")
+				fs.DurationVar(&captureArgs.duration, "duration", 0, "stop the capture after this long; 0 means until interrupted")
 				return fs
 			})(),
 		},
@@ -503,6 +509,76 @@ func runWatchIPN(ctx context.Context, args []string) error {
 
 var netmapArgs struct {
 	showPrivateKey bool
+	section        string // comma-separated netmapSectionNames, or empty for all
+}
+
+// netmapSectionNames are the valid values of "debug netmap --section".
+var netmapSectionNames = []string{"self", "peers", "derp", "dns", "packetfilter"}
+
+// netmapFilterSummary summarizes a netmap's packet filter for
+// "debug netmap --section=packetfilter".
+type netmapFilterSummary struct {
+	Rules    int      // number of filter rules
+	SrcIPs   []string // unique source IPs of all rules, sorted
+	DstPorts []string // unique destinations of all rules as "ip:ports", sorted
+}
+
+func summarizePacketFilter(rules views.Slice[tailcfg.FilterRule]) netmapFilterSummary {
+	var srcs, dsts []string
+	for i := range rules.Len() {
+		r := rules.At(i)
+		srcs = append(srcs, r.SrcIPs...)
+		for _, d := range r.DstPorts {
+			ports := fmt.Sprint(d.Ports.First)
+			if d.Ports.First != d.Ports.Last {
+				ports = fmt.Sprintf("%d-%d", d.Ports.First, d.Ports.Last)
+			}
+			dsts = append(dsts, d.IP+":"+ports)
+		}
+	}
+	slices.Sort(srcs)
+	slices.Sort(dsts)
+	return netmapFilterSummary{
+		Rules:    rules.Len(),
+		SrcIPs:   slices.Compact(srcs),
+		DstPorts: slices.Compact(dsts),
+	}
+}
+
+// netmapOutput returns the value to print for "debug netmap": the whole
+// netmap if sections is empty, or else an object with just the named
+// comma-separated sections. Unless showPrivateKey is set, the node's
+// private key is redacted.
+func netmapOutput(nm *netmap.NetworkMap, sections string, showPrivateKey bool) (any, error) {
+	if nm != nil && !showPrivateKey && !nm.PrivateKey.IsZero() {
+		redacted := *nm
+		redacted.PrivateKey = key.NodePrivate{}
+		nm = &redacted
+	}
+	if sections == "" {
+		return nm, nil
+	}
+	if nm == nil {
+		return nil, errors.New("no netmap available")
+	}
+	out := map[string]any{}
+	for _, name := range strings.Split(sections, ",") {
+		switch name {
+		case "self":
+			out[name] = nm.SelfNode
+		case "peers":
+			out[name] = nm.Peers
+		case "derp":
+			out[name] = nm.DERPMap
+		case "dns":
+			out[name] = nm.DNS
+		case "packetfilter":
+			out[name] = summarizePacketFilter(nm.PacketFilterRules)
+		default:
+			return nil, fmt.Errorf("unknown netmap section %q; must be one of %s", name, strings.Join(netmapSectionNames, ", "))
+		}
+	}
+	return out, nil
 }
 
 func runNetmap(ctx context.Context, args []string) error {
@@ -523,7 +599,11 @@ func runNetmap(ctx context.Context, args []string) error {
 	if err != nil {
 		return err
 	}
-	j, _ := json.MarshalIndent(n.NetMap, "", "\t")
+	out, err := netmapOutput(n.NetMap, netmapArgs.section, netmapArgs.showPrivateKey)
+	if err != nil {
+		return err
+	}
+	j, _ := json.MarshalIndent(out, "", "\t")
 	fmt.Printf("%s\n", j)
 	return nil
 }
@@ -906,11 +986,22 @@ func runSetExpire(ctx context.Context, args []string) error {
 }
 
 var captureArgs struct {
-	outFile string
+	outFile  string
+	duration time.Duration
 }
 
 func runCapture(ctx context.Context, args []string) error {
-	stream, err := localClient.StreamDebugCapture(ctx)
+	// captureCtx bounds the capture stream only. Wireshark is started with
+	// ctx, so that it keeps running once the capture stops.
+	captureCtx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+	if captureArgs.duration > 0 {
+		var cancelTimeout context.CancelFunc
+		captureCtx, cancelTimeout = context.WithTimeout(captureCtx, captureArgs.duration)
+		defer cancelTimeout()
+	}
+
+	stream, err := localClient.StreamDebugCapture(captureCtx)
 	if err != nil {
 		return err
 	}
@@ -919,8 +1010,7 @@ func runCapture(ctx context.Context, args []string) error {
 	switch captureArgs.outFile {
 	case "-":
 		fmt.Fprintln(Stderr, "Press Ctrl-C to stop the capture.")
-		_, err = io.Copy(os.Stdout, stream)
-		return err
+		return copyCapture(captureCtx, Stdout, stream)
 	case "":
 		lua, err := os.CreateTemp("", "ts-dissector")
 		if err != nil {
@@ -933,10 +1023,9 @@ func runCapture(ctx context.Context, args []string) error {
 		}
 
 		wireshark := exec.CommandContext(ctx, "wireshark", "-X", "lua_script:"+lua.Name(), "-k", "-i", "-")
-		wireshark.Stdin = stream
 		wireshark.Stdout = os.Stdout
 		wireshark.Stderr = os.Stderr
-		return wireshark.Run()
+		return feedCapture(captureCtx, wireshark, stream)
 	}
 
 	f, err := os.OpenFile(captureArgs.outFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
@@ -945,8 +1034,85 @@ func runCapture(ctx context.Context, args []string) error {
 	}
 	defer f.Close()
 	fmt.Fprintln(Stderr, "Press Ctrl-C to stop the capture.")
-	_, err = io.Copy(f, stream)
-	return err
+	if err := copyCapture(captureCtx, f, stream); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// feedCapture starts cmd and copies the pcap stream r to its stdin until r
+// ends or captureCtx is done. It then closes cmd's stdin, so that it sees
+// EOF, and waits for cmd to exit, which for Wireshark is when the user
+// quits it.
+func feedCapture(captureCtx context.Context, cmd *exec.Cmd, r io.Reader) error {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if err := copyCapture(captureCtx, stdin, r); err != nil {
+		// cmd may have stopped reading, such as if the user quit
+		// Wireshark before the capture ended, so don't fail.
+		fmt.Fprintf(Stderr, "capture stopped: %v\n", err)
+	}
+	stdin.Close()
+	return cmd.Wait()
+}
+
+const (
+	pcapMagic           = 0xA1B2C3D4
+	pcapHeaderLen       = 24 // global header
+	pcapRecordHeaderLen = 16 // per-packet header
+	pcapMaxPacketLen    = 1 << 20
+)
+
+// copyCapture copies the pcap stream r to w, one whole packet record at a
+// time, so that w holds a well-formed pcap file even if the capture is
+// stopped mid-packet. Once ctx is done, errors reading r are treated as a
+// graceful stop rather than a failure.
+func copyCapture(ctx context.Context, w io.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+	readErr := func(what string, err error) error {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("reading %s: %w", what, err)
+	}
+
+	var hdr [pcapHeaderLen]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil {
+		return readErr("pcap header", err)
+	}
+	if binary.LittleEndian.Uint32(hdr[:]) != pcapMagic {
+		return errors.New("capture stream is not in pcap format")
+	}
+	if _, err := w.Write(hdr[:]); err != nil {
+		return err
+	}
+
+	var rec []byte
+	for {
+		rec = append(rec[:0], make([]byte, pcapRecordHeaderLen)...)
+		if _, err := io.ReadFull(br, rec); err != nil {
+			if err == io.EOF {
+				return nil // stream ended between packets
+			}
+			return readErr("packet header", err)
+		}
+		n := binary.LittleEndian.Uint32(rec[8:12]) // captured length
+		if n > pcapMaxPacketLen {
+			return fmt.Errorf("invalid pcap packet length %d", n)
+		}
+		rec = append(rec, make([]byte, n)...)
+		if _, err := io.ReadFull(br, rec[pcapRecordHeaderLen:]); err != nil {
+			return readErr("packet", err)
+		}
+		if _, err := w.Write(rec); err != nil {
+			return err
+		}
+	}
 }
 
 var debugPortmapArgs struct {
@@ -0,0 +1,235 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+	"tailscale.com/types/netmap"
+	"tailscale.com/types/views"
+)
+
+// fakePcap returns a pcap stream with a global header followed by a record
+// for each packet.
+func fakePcap(packets ...string) []byte {
+	var b bytes.Buffer
+	for _, v := range []any{uint32(pcapMagic), uint16(2), uint16(4), uint32(0), uint32(0), uint32(65535), uint32(147)} {
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	for i, p := range packets {
+		for _, v := range []uint32{uint32(1700000000 + i), 0, uint32(len(p)), uint32(len(p))} {
+			binary.Write(&b, binary.LittleEndian, v)
+		}
+		b.WriteString(p)
+	}
+	return b.Bytes()
+}
+
+// checkPcap verifies that b is a well-formed pcap file and returns the
+// packets in it.
+func checkPcap(t *testing.T, b []byte) []string {
+	t.Helper()
+	if len(b) < pcapHeaderLen || binary.LittleEndian.Uint32(b) != pcapMagic {
+		t.Fatalf("missing pcap header: % x", b)
+	}
+	b = b[pcapHeaderLen:]
+	var packets []string
+	for len(b) > 0 {
+		if len(b) < pcapRecordHeaderLen {
+			t.Fatalf("truncated packet header: % x", b)
+		}
+		n := int(binary.LittleEndian.Uint32(b[8:12]))
+		b = b[pcapRecordHeaderLen:]
+		if len(b) < n {
+			t.Fatalf("truncated packet: want %d bytes, have %d", n, len(b))
+		}
+		packets = append(packets, string(b[:n]))
+		b = b[n:]
+	}
+	return packets
+}
+
+// errAfterReader returns the contents of r, then err.
+type errAfterReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *errAfterReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func TestCopyCapture(t *testing.T) {
+	t.Run("complete", func(t *testing.T) {
+		in := fakePcap("hello", "", "world")
+		var out bytes.Buffer
+		if err := copyCapture(context.Background(), &out, bytes.NewReader(in)); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out.Bytes(), in) {
+			t.Errorf("output differs from input:\ngot  % x\nwant % x", out.Bytes(), in)
+		}
+		if got := checkPcap(t, out.Bytes()); strings.Join(got, ",") != "hello,,world" {
+			t.Errorf("got packets %q", got)
+		}
+	})
+
+	t.Run("stopped-mid-packet", func(t *testing.T) {
+		in := fakePcap("hello", "world")
+		in = in[:len(in)-2] // cut off the end of the second packet
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var out bytes.Buffer
+		if err := copyCapture(ctx, &out, &errAfterReader{bytes.NewReader(in), context.Canceled}); err != nil {
+			t.Fatalf("graceful stop returned error: %v", err)
+		}
+		if got := checkPcap(t, out.Bytes()); strings.Join(got, ",") != "hello" {
+			t.Errorf("got packets %q; want [hello]", got)
+		}
+	})
+
+	t.Run("stream-error", func(t *testing.T) {
+		in := fakePcap("hello", "world")
+		in = in[:len(in)-2]
+		var out bytes.Buffer
+		err := copyCapture(context.Background(), &out, &errAfterReader{bytes.NewReader(in), io.ErrUnexpectedEOF})
+		if err == nil {
+			t.Fatal("truncated stream: got nil error")
+		}
+		checkPcap(t, out.Bytes())
+	})
+
+	t.Run("not-pcap", func(t *testing.T) {
+		var out bytes.Buffer
+		err := copyCapture(context.Background(), &out, strings.NewReader(strings.Repeat("x", 100)))
+		if err == nil || !strings.Contains(err.Error(), "not in pcap format") {
+			t.Errorf("got error %v; want not in pcap format", err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("wrote %d bytes for invalid stream", out.Len())
+		}
+	})
+}
+
+// ctxReader blocks until ctx is done, then returns its error.
+type ctxReader struct{ ctx context.Context }
+
+func (r ctxReader) Read([]byte) (int, error) {
+	<-r.ctx.Done()
+	return 0, r.ctx.Err()
+}
+
+func TestFeedCapture(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("no cat binary")
+	}
+	// The capture stops when captureCtx times out, while the command,
+	// standing in for Wireshark, is only stopped by EOF on its stdin.
+	captureCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	in := fakePcap("hello")
+	var out bytes.Buffer
+	cmd := exec.CommandContext(context.Background(), "cat")
+	cmd.Stdout = &out
+	if err := feedCapture(captureCtx, cmd, io.MultiReader(bytes.NewReader(in), ctxReader{captureCtx})); err != nil {
+		t.Fatalf("command did not exit cleanly: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), in) {
+		t.Errorf("command got % x; want % x", out.Bytes(), in)
+	}
+}
+
+func TestNetmapOutput(t *testing.T) {
+	nm := &netmap.NetworkMap{
+		SelfNode:   (&tailcfg.Node{ID: 1, Name: "self.test.ts.net."}).View(),
+		PrivateKey: key.NewNode(),
+		Peers: []tailcfg.NodeView{
+			(&tailcfg.Node{ID: 2, Name: "peer.test.ts.net."}).View(),
+		},
+		DNS: tailcfg.DNSConfig{Domains: []string{"test.ts.net"}},
+		DERPMap: &tailcfg.DERPMap{
+			Regions: map[int]*tailcfg.DERPRegion{1: {RegionID: 1, RegionCode: "r1"}},
+		},
+		PacketFilterRules: views.SliceOf([]tailcfg.FilterRule{
+			{
+				SrcIPs:   []string{"100.64.0.2", "100.64.0.1"},
+				DstPorts: []tailcfg.NetPortRange{{IP: "*", Ports: tailcfg.PortRange{First: 22, Last: 22}}},
+			},
+			{
+				SrcIPs:   []string{"100.64.0.1"},
+				DstPorts: []tailcfg.NetPortRange{{IP: "100.64.0.3", Ports: tailcfg.PortRange{First: 80, Last: 443}}},
+			},
+		}),
+	}
+	marshal := func(sections string, showPrivateKey bool) string {
+		t.Helper()
+		v, err := netmapOutput(nm, sections, showPrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		j, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(j)
+	}
+	privKey, _ := nm.PrivateKey.MarshalText()
+
+	if got := marshal("", false); strings.Contains(got, string(privKey)) {
+		t.Errorf("private key not redacted: %s", got)
+	}
+	if got := marshal("", true); !strings.Contains(got, string(privKey)) {
+		t.Errorf("private key missing with showPrivateKey: %s", got)
+	}
+	if nm.PrivateKey.IsZero() {
+		t.Error("netmapOutput modified its input")
+	}
+
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(marshal("self,packetfilter", false)), &got); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for k := range got {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if want := []string{"packetfilter", "self"}; !slices.Equal(keys, want) {
+		t.Errorf("sections = %q, want %q", keys, want)
+	}
+	if !strings.Contains(string(got["self"]), "self.test.ts.net.") {
+		t.Errorf("self = %s", got["self"])
+	}
+	var pf netmapFilterSummary
+	if err := json.Unmarshal(got["packetfilter"], &pf); err != nil {
+		t.Fatal(err)
+	}
+	wantPF := netmapFilterSummary{
+		Rules:    2,
+		SrcIPs:   []string{"100.64.0.1", "100.64.0.2"},
+		DstPorts: []string{"*:22", "100.64.0.3:80-443"},
+	}
+	if pf.Rules != wantPF.Rules || !slices.Equal(pf.SrcIPs, wantPF.SrcIPs) || !slices.Equal(pf.DstPorts, wantPF.DstPorts) {
+		t.Errorf("packetfilter = %+v, want %+v", pf, wantPF)
+	}
+
+	if _, err := netmapOutput(nm, "self,bogus", false); err == nil {
+		t.Error("unknown section succeeded")
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"tailscale.com/ipn"
+	"tailscale.com/ipn/ipnstate"
+	"tailscale.com/net/netcheck"
+	"tailscale.com/net/tlsdial"
+)
+
+var doctorCmd = &ffcli.Command{
+	Name:       "doctor",
+	ShortUsage: "tailscale doctor [--json]",
+	ShortHelp:  "Run diagnostics and suggest fixes for common problems",
+	LongHelp: strings.TrimSpace(`
+'tailscale doctor' runs a set of checks on the local Tailscale installation
+and network: whether tailscaled is reachable, whether you're logged in, whether
+DNS and DERP relays work, whether the node key is about to expire, and whether
+the system clock is accurate.
+
+Each check is reported as pass, warn, or fail, along with a hint on how to fix
+it. The command exits with a non-zero status if any check fails.
+`),
+	Exec: runDoctor,
+	FlagSet: (func() *flag.FlagSet {
+		fs := newFlagSet("doctor")
+		fs.BoolVar(&doctorArgs.json, "json", false, "output in JSON format")
+		return fs
+	})(),
+}
+
+var doctorArgs struct {
+	json bool // output in JSON format
+}
+
+const (
+	// doctorKeyExpiryWarn is how long before key expiry the doctor
+	// command starts warning about it.
+	doctorKeyExpiryWarn = 7 * 24 * time.Hour
+
+	// doctorMaxClockSkew is the largest clock difference from the control
+	// server that's considered in sync.
+	doctorMaxClockSkew = time.Minute
+
+	// doctorDNSName is the name resolved to verify that DNS works.
+	doctorDNSName = "controlplane.tailscale.com"
+
+	// doctorCheckTimeout bounds each network check.
+	doctorCheckTimeout = 10 * time.Second
+)
+
+// doctorResult is the outcome of a single doctor check.
+type doctorResult string
+
+const (
+	doctorPass doctorResult = "pass"
+	doctorWarn doctorResult = "warn"
+	doctorFail doctorResult = "fail"
+)
+
+// doctorCheck is a single line of the doctor checklist.
+type doctorCheck struct {
+	Name   string
+	Result doctorResult
+	Detail string
+	Hint   string `json:",omitempty"` // remediation hint for warn and fail
+}
+
+// doctorEnv holds the backends used by the doctor checks, so that they can
+// be faked out in tests.
+type doctorEnv struct {
+	status     func(context.Context) (*ipnstate.Status, error)
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+	netcheck   func(context.Context) (*netcheck.Report, error)
+	prefs      func(context.Context) (*ipn.Prefs, error)
+	serverTime func(ctx context.Context, controlURL string) (time.Time, error)
+	now        func() time.Time
+}
+
+func runDoctor(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		return errors.New("unexpected non-flag arguments to 'tailscale doctor'")
+	}
+	env := &doctorEnv{
+		status:     localClient.Status,
+		lookupHost: net.DefaultResolver.LookupHost,
+		netcheck:   doctorNetcheck,
+		prefs:      localClient.GetPrefs,
+		serverTime: doctorServerTime,
+		now:        time.Now,
+	}
+	checks := env.run(ctx)
+	if doctorArgs.json {
+		j, err := json.MarshalIndent(checks, "", "  ")
+		if err != nil {
+			return err
+		}
+		j = append(j, '\n')
+		Stdout.Write(j)
+	} else {
+		printDoctorChecks(Stdout, checks)
+	}
+	var failed int
+	for _, c := range checks {
+		if c.Result == doctorFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// run runs all checks and returns their results in order.
+func (e *doctorEnv) run(ctx context.Context) []doctorCheck {
+	var checks []doctorCheck
+	st, err := e.status(ctx)
+	checks = append(checks, checkDoctorDaemon(st, err))
+	if err == nil {
+		checks = append(checks, checkDoctorLogin(st), checkDoctorKeyExpiry(st, e.now()))
+	}
+
+	dnsCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	_, err = e.lookupHost(dnsCtx, doctorDNSName)
+	cancel()
+	checks = append(checks, checkDoctorDNS(err))
+
+	ncCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	report, err := e.netcheck(ncCtx)
+	cancel()
+	checks = append(checks, checkDoctorDERP(report, err))
+
+	timeCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	t, err := e.serverTime(timeCtx, e.controlURL(timeCtx))
+	cancel()
+	checks = append(checks, checkDoctorClock(t, e.now(), err))
+	return checks
+}
+
+// controlURL returns the control server URL the node is configured to use,
+// or the default control server if the prefs can't be fetched.
+func (e *doctorEnv) controlURL(ctx context.Context) string {
+	prefs, err := e.prefs(ctx)
+	if err != nil {
+		return ipn.DefaultControlURL
+	}
+	return prefs.ControlURLOrDefault()
+}
+
+func checkDoctorDaemon(st *ipnstate.Status, err error) doctorCheck {
+	c := doctorCheck{Name: "tailscaled reachable"}
+	if err != nil {
+		c.Result = doctorFail
+		c.Detail = fixTailscaledConnectError(err).Error()
+		c.Hint = "make sure tailscaled is installed and running"
+		return c
+	}
+	c.Result = doctorPass
+	c.Detail = "tailscaled version " + st.Version
+	return c
+}
+
+func checkDoctorLogin(st *ipnstate.Status) doctorCheck {
+	c := doctorCheck{Name: "logged in", Detail: "backend state is " + st.BackendState}
+	switch st.BackendState {
+	case ipn.Running.String():
+		c.Result = doctorPass
+	case ipn.NeedsLogin.String():
+		c.Result = doctorFail
+		c.Hint = `run "tailscale up" to log in`
+	case ipn.NeedsMachineAuth.String():
+		c.Result = doctorFail
+		c.Hint = "ask a tailnet admin to approve this device"
+	case ipn.Stopped.String():
+		c.Result = doctorWarn
+		c.Hint = `run "tailscale up" to connect`
+	default:
+		c.Result = doctorWarn
+		c.Hint = "wait for tailscaled to finish starting, then try again"
+	}
+	return c
+}
+
+func checkDoctorKeyExpiry(st *ipnstate.Status, now time.Time) doctorCheck {
+	c := doctorCheck{Name: "node key expiry"}
+	if st.Self == nil || st.Self.KeyExpiry == nil {
+		c.Result = doctorPass
+		c.Detail = "key does not expire"
+		return c
+	}
+	left := st.Self.KeyExpiry.Sub(now)
+	switch {
+	case left <= 0:
+		c.Result = doctorFail
+		c.Detail = fmt.Sprintf("key expired at %v", st.Self.KeyExpiry.Format(time.RFC3339))
+		c.Hint = `run "tailscale up --force-reauth" to re-authenticate`
+	case left < doctorKeyExpiryWarn:
+		c.Result = doctorWarn
+		c.Detail = fmt.Sprintf("key expires in %v", left.Round(time.Minute))
+		c.Hint = `run "tailscale up --force-reauth" or disable key expiry in the admin console`
+	default:
+		c.Result = doctorPass
+		c.Detail = fmt.Sprintf("key expires at %v", st.Self.KeyExpiry.Format(time.RFC3339))
+	}
+	return c
+}
+
+func checkDoctorDNS(err error) doctorCheck {
+	c := doctorCheck{Name: "DNS"}
+	if err != nil {
+		c.Result = doctorFail
+		c.Detail = fmt.Sprintf("resolving %s: %v", doctorDNSName, err)
+		c.Hint = "check the system DNS configuration and network connectivity"
+		return c
+	}
+	c.Result = doctorPass
+	c.Detail = "resolved " + doctorDNSName
+	return c
+}
+
+func checkDoctorDERP(report *netcheck.Report, err error) doctorCheck {
+	c := doctorCheck{Name: "DERP"}
+	switch {
+	case err != nil:
+		c.Result = doctorFail
+		c.Detail = err.Error()
+		c.Hint = `run "tailscale netcheck --verbose" for details`
+	case len(report.RegionLatency) == 0:
+		c.Result = doctorFail
+		c.Detail = "no DERP region responded"
+		c.Hint = "check that outbound HTTPS and UDP port 3478 are allowed by your firewall"
+	case !report.UDP:
+		c.Result = doctorWarn
+		c.Detail = fmt.Sprintf("%d regions reachable, but UDP is blocked", len(report.RegionLatency))
+		c.Hint = "allow outbound UDP for direct connections; traffic will be relayed until then"
+	default:
+		c.Result = doctorPass
+		c.Detail = fmt.Sprintf("%d regions reachable", len(report.RegionLatency))
+	}
+	return c
+}
+
+func checkDoctorClock(serverTime, now time.Time, err error) doctorCheck {
+	c := doctorCheck{Name: "clock"}
+	if err != nil {
+		c.Result = doctorWarn
+		c.Detail = fmt.Sprintf("could not fetch time from control server: %v", err)
+		c.Hint = "check network connectivity to the control server"
+		return c
+	}
+	skew := now.Sub(serverTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > doctorMaxClockSkew {
+		c.Result = doctorFail
+		c.Detail = fmt.Sprintf("system clock is off by %v", skew.Round(time.Second))
+		c.Hint = "enable time synchronization (NTP) on this device"
+		return c
+	}
+	c.Result = doctorPass
+	c.Detail = "system clock is in sync"
+	return c
+}
+
+// printDoctorChecks writes checks to w as a human-readable checklist.
+func printDoctorChecks(w io.Writer, checks []doctorCheck) {
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	for _, c := range checks {
+		fmt.Fprintf(tw, "[%s]\t%s:\t%s\n", c.Result, c.Name, c.Detail)
+		if c.Hint != "" {
+			fmt.Fprintf(tw, "\t\t  hint: %s\n", c.Hint)
+		}
+	}
+	tw.Flush()
+}
+
+// doctorNetcheck runs a single netcheck report, as "tailscale netcheck" does.
+func doctorNetcheck(ctx context.Context) (*netcheck.Report, error) {
+	c, err := newNetcheckClient(false)
+	if err != nil {
+		return nil, err
+	}
+	dm, err := netcheckDERPMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetReport(ctx, dm, nil)
+}
+
+// doctorServerTime returns the current time according to the HTTP Date
+// header of the control server at controlURL.
+func doctorServerTime(ctx context.Context, controlURL string) (time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", controlURL, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	hc := &http.Client{Transport: tlsdial.NewTransport()}
+	res, err := hc.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	res.Body.Close()
+	return http.ParseTime(res.Header.Get("Date"))
+}
@@ -0,0 +1,219 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"tailscale.com/ipn"
+	"tailscale.com/ipn/ipnstate"
+	"tailscale.com/net/netcheck"
+	"tailscale.com/types/ptr"
+)
+
+func TestDoctorChecks(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	healthyStatus := &ipnstate.Status{
+		Version:      "1.2.3",
+		BackendState: ipn.Running.String(),
+		Self:         &ipnstate.PeerStatus{KeyExpiry: ptr.To(now.Add(90 * 24 * time.Hour))},
+	}
+	healthyReport := &netcheck.Report{UDP: true, RegionLatency: map[int]time.Duration{1: 10 * time.Millisecond}}
+
+	// newEnv returns a doctorEnv where every check passes, modified by mod.
+	newEnv := func(mod func(*doctorEnv)) *doctorEnv {
+		e := &doctorEnv{
+			status: func(context.Context) (*ipnstate.Status, error) { return healthyStatus, nil },
+			lookupHost: func(context.Context, string) ([]string, error) {
+				return []string{"192.0.2.1"}, nil
+			},
+			netcheck:   func(context.Context) (*netcheck.Report, error) { return healthyReport, nil },
+			prefs:      func(context.Context) (*ipn.Prefs, error) { return ipn.NewPrefs(), nil },
+			serverTime: func(context.Context, string) (time.Time, error) { return now.Add(2 * time.Second), nil },
+			now:        func() time.Time { return now },
+		}
+		if mod != nil {
+			mod(e)
+		}
+		return e
+	}
+	statusWith := func(mod func(*ipnstate.Status)) func(*doctorEnv) {
+		return func(e *doctorEnv) {
+			st := *healthyStatus
+			self := *st.Self
+			st.Self = &self
+			mod(&st)
+			e.status = func(context.Context) (*ipnstate.Status, error) { return &st, nil }
+		}
+	}
+
+	tests := []struct {
+		name  string
+		mod   func(*doctorEnv)
+		check string       // name of the check of interest
+		want  doctorResult // empty means the check should be absent
+	}{
+		{name: "all-pass", check: "tailscaled reachable", want: doctorPass},
+		{
+			name: "daemon-down",
+			mod: func(e *doctorEnv) {
+				e.status = func(context.Context) (*ipnstate.Status, error) { return nil, errors.New("connection refused") }
+			},
+			check: "tailscaled reachable",
+			want:  doctorFail,
+		},
+		{
+			name: "daemon-down-skips-login",
+			mod: func(e *doctorEnv) {
+				e.status = func(context.Context) (*ipnstate.Status, error) { return nil, errors.New("connection refused") }
+			},
+			check: "logged in",
+		},
+		{
+			name:  "needs-login",
+			mod:   statusWith(func(st *ipnstate.Status) { st.BackendState = ipn.NeedsLogin.String() }),
+			check: "logged in",
+			want:  doctorFail,
+		},
+		{
+			name:  "stopped",
+			mod:   statusWith(func(st *ipnstate.Status) { st.BackendState = ipn.Stopped.String() }),
+			check: "logged in",
+			want:  doctorWarn,
+		},
+		{
+			name:  "key-expiring-soon",
+			mod:   statusWith(func(st *ipnstate.Status) { st.Self.KeyExpiry = ptr.To(now.Add(time.Hour)) }),
+			check: "node key expiry",
+			want:  doctorWarn,
+		},
+		{
+			name:  "key-expired",
+			mod:   statusWith(func(st *ipnstate.Status) { st.Self.KeyExpiry = ptr.To(now.Add(-time.Hour)) }),
+			check: "node key expiry",
+			want:  doctorFail,
+		},
+		{
+			name:  "key-expiry-disabled",
+			mod:   statusWith(func(st *ipnstate.Status) { st.Self.KeyExpiry = nil }),
+			check: "node key expiry",
+			want:  doctorPass,
+		},
+		{
+			name: "dns-broken",
+			mod: func(e *doctorEnv) {
+				e.lookupHost = func(context.Context, string) ([]string, error) { return nil, errors.New("no such host") }
+			},
+			check: "DNS",
+			want:  doctorFail,
+		},
+		{
+			name: "derp-unreachable",
+			mod: func(e *doctorEnv) {
+				e.netcheck = func(context.Context) (*netcheck.Report, error) { return &netcheck.Report{}, nil }
+			},
+			check: "DERP",
+			want:  doctorFail,
+		},
+		{
+			name: "udp-blocked",
+			mod: func(e *doctorEnv) {
+				e.netcheck = func(context.Context) (*netcheck.Report, error) {
+					return &netcheck.Report{RegionLatency: healthyReport.RegionLatency}, nil
+				}
+			},
+			check: "DERP",
+			want:  doctorWarn,
+		},
+		{
+			name: "clock-skewed",
+			mod: func(e *doctorEnv) {
+				e.serverTime = func(context.Context, string) (time.Time, error) { return now.Add(-10 * time.Minute), nil }
+			},
+			check: "clock",
+			want:  doctorFail,
+		},
+		{
+			name: "clock-unknown",
+			mod: func(e *doctorEnv) {
+				e.serverTime = func(context.Context, string) (time.Time, error) { return time.Time{}, errors.New("timeout") }
+			},
+			check: "clock",
+			want:  doctorWarn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := newEnv(tt.mod).run(context.Background())
+			var got doctorResult
+			for _, c := range checks {
+				if c.Name == tt.check {
+					got = c.Result
+					if c.Result != doctorPass && c.Hint == "" {
+						t.Errorf("check %q has result %q but no hint", c.Name, c.Result)
+					}
+				} else if c.Result != doctorPass && tt.mod == nil {
+					t.Errorf("check %q = %q; want pass", c.Name, c.Result)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("check %q = %q; want %q", tt.check, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoctorControlURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		prefs *ipn.Prefs
+		err   error
+		want  string
+	}{
+		{name: "custom", prefs: &ipn.Prefs{ControlURL: "https://control.example.com"}, want: "https://control.example.com"},
+		{name: "empty", prefs: &ipn.Prefs{}, want: ipn.DefaultControlURL},
+		{name: "prefs-error", err: errors.New("no tailscaled"), want: ipn.DefaultControlURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			e := &doctorEnv{
+				status: func(context.Context) (*ipnstate.Status, error) { return nil, errors.New("down") },
+				lookupHost: func(context.Context, string) ([]string, error) {
+					return nil, errors.New("down")
+				},
+				netcheck: func(context.Context) (*netcheck.Report, error) { return nil, errors.New("down") },
+				prefs:    func(context.Context) (*ipn.Prefs, error) { return tt.prefs, tt.err },
+				serverTime: func(_ context.Context, controlURL string) (time.Time, error) {
+					got = controlURL
+					return time.Now(), nil
+				},
+				now: time.Now,
+			}
+			e.run(context.Background())
+			if got != tt.want {
+				t.Errorf("serverTime called with %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintDoctorChecks(t *testing.T) {
+	var buf bytes.Buffer
+	printDoctorChecks(&buf, []doctorCheck{
+		{Name: "DNS", Result: doctorPass, Detail: "resolved"},
+		{Name: "clock", Result: doctorFail, Detail: "off by 10m0s", Hint: "enable NTP"},
+	})
+	got := buf.String()
+	for _, want := range []string{"[pass]", "DNS:", "[fail]", "clock:", "hint: enable NTP"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -6,16 +6,22 @@
 import (
 	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net/netip"
 	"slices"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
 	xmaps "golang.org/x/exp/maps"
+	"tailscale.com/client/tailscale/apitype"
 	"tailscale.com/envknob"
+	"tailscale.com/ipn"
 	"tailscale.com/ipn/ipnstate"
 	"tailscale.com/tailcfg"
 )
@@ -39,9 +45,15 @@ func exitNodeCmd() *ffcli.Command {
 			},
 			{
 				Name:       "suggest",
-				ShortUsage: "tailscale exit-node suggest",
+				ShortUsage: "tailscale exit-node suggest [flags]",
 				ShortHelp:  "Suggests the best available exit node",
 				Exec:       runExitNodeSuggest,
+				FlagSet: (func() *flag.FlagSet {
+					fs := newFlagSet("suggest")
+					fs.BoolVar(&exitNodeArgs.json, "json", false, "output in JSON format")
+					fs.BoolVar(&exitNodeArgs.set, "set", false, "use the suggested exit node immediately")
+					return fs
+				})(),
 			}},
 			(func() []*ffcli.Command {
 				if !envknob.UseWIPCode() {
@@ -67,6 +79,8 @@ func exitNodeCmd() *ffcli.Command {
 
 var exitNodeArgs struct {
 	filter string
+	json   bool // output suggestion in JSON format
+	set    bool // apply the suggested exit node
 }
 
 func exitNodeSetUse(wantOn bool) func(ctx context.Context, args []string) error {
@@ -143,21 +157,135 @@ func runExitNodeList(ctx context.Context, args []string) error {
 	return nil
 }
 
+// exitNodeSuggestPingTimeout bounds the ping used to measure the latency
+// to a suggested exit node.
+const exitNodeSuggestPingTimeout = 5 * time.Second
+
+// exitNodeSuggestion is the output of "tailscale exit-node suggest".
+type exitNodeSuggestion struct {
+	ID       tailcfg.StableNodeID
+	Name     string
+	Location *tailcfg.Location `json:",omitempty"`
+
+	// LatencySeconds is the measured round-trip latency to the suggested
+	// exit node, or zero if it could not be measured.
+	LatencySeconds float64 `json:",omitempty"`
+
+	// Applied is whether the suggestion was set as the current exit node.
+	Applied bool `json:",omitempty"`
+}
+
+// exitNodeSuggestEnv holds the LocalAPI methods used by
+// "tailscale exit-node suggest", so that they can be faked out in tests.
+type exitNodeSuggestEnv struct {
+	suggest   func(context.Context) (apitype.ExitNodeSuggestionResponse, error)
+	status    func(context.Context) (*ipnstate.Status, error)
+	ping      func(context.Context, netip.Addr, tailcfg.PingType) (*ipnstate.PingResult, error)
+	editPrefs func(context.Context, *ipn.MaskedPrefs) (*ipn.Prefs, error)
+}
+
 // runExitNodeSuggest returns a suggested exit node ID to connect to and shows the chosen exit node tailcfg.StableNodeID.
 // If there are no derp based exit nodes to choose from or there is a failure in finding a suggestion, the command will return an error indicating so.
 func runExitNodeSuggest(ctx context.Context, args []string) error {
-	res, err := localClient.SuggestExitNode(ctx)
+	if len(args) > 0 {
+		return errors.New("unexpected non-flag arguments to 'tailscale exit-node suggest'")
+	}
+	env := &exitNodeSuggestEnv{
+		suggest:   localClient.SuggestExitNode,
+		status:    localClient.Status,
+		ping:      localClient.Ping,
+		editPrefs: localClient.EditPrefs,
+	}
+	return env.run(ctx, Stdout, exitNodeArgs.json, exitNodeArgs.set)
+}
+
+func (e *exitNodeSuggestEnv) run(ctx context.Context, w io.Writer, asJSON, set bool) error {
+	res, err := e.suggest(ctx)
 	if err != nil {
 		return fmt.Errorf("suggest exit node: %w", err)
 	}
 	if res.ID == "" {
-		fmt.Println("No exit node suggestion is available.")
+		if asJSON {
+			fmt.Fprintln(w, "null")
+			return nil
+		}
+		fmt.Fprintln(w, "No exit node suggestion is available.")
 		return nil
 	}
-	fmt.Printf("Suggested exit node: %v\nTo accept this suggestion, use `tailscale set --exit-node=%v`.\n", res.Name, res.ID)
+	sug := exitNodeSuggestion{
+		ID:             res.ID,
+		Name:           res.Name,
+		Location:       res.Location.AsStruct(),
+		LatencySeconds: e.latency(ctx, res.ID),
+	}
+	if set {
+		if _, err := e.editPrefs(ctx, &ipn.MaskedPrefs{
+			Prefs: ipn.Prefs{
+				ExitNodeID: res.ID,
+			},
+			ExitNodeIDSet: true,
+			ExitNodeIPSet: true,
+		}); err != nil {
+			return fmt.Errorf("setting exit node: %w", err)
+		}
+		sug.Applied = true
+	}
+	if asJSON {
+		j, err := json.MarshalIndent(sug, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\n", j)
+		return nil
+	}
+	printExitNodeSuggestion(w, sug)
 	return nil
 }
 
+// latency returns the round-trip latency in seconds to the peer with the
+// given ID, or zero if it can't be measured.
+func (e *exitNodeSuggestEnv) latency(ctx context.Context, id tailcfg.StableNodeID) float64 {
+	st, err := e.status(ctx)
+	if err != nil {
+		return 0
+	}
+	for _, ps := range st.Peer {
+		if ps.ID != id || len(ps.TailscaleIPs) == 0 {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(ctx, exitNodeSuggestPingTimeout)
+		defer cancel()
+		pr, err := e.ping(ctx, ps.TailscaleIPs[0], tailcfg.PingDisco)
+		if err != nil || pr.Err != "" {
+			return 0
+		}
+		return pr.LatencySeconds
+	}
+	return 0
+}
+
+// printExitNodeSuggestion writes a human-readable description of sug to w.
+func printExitNodeSuggestion(w io.Writer, sug exitNodeSuggestion) {
+	fmt.Fprintf(w, "Suggested exit node: %v\n", sug.Name)
+	if loc := sug.Location; loc != nil && loc.Country != "" {
+		if loc.City != "" {
+			fmt.Fprintf(w, "Location: %s, %s\n", loc.City, loc.Country)
+		} else {
+			fmt.Fprintf(w, "Location: %s\n", loc.Country)
+		}
+	}
+	if sug.LatencySeconds > 0 {
+		fmt.Fprintf(w, "Latency: %v\n", time.Duration(sug.LatencySeconds*float64(time.Second)).Round(time.Millisecond/10))
+	} else {
+		fmt.Fprintln(w, "Latency: unknown")
+	}
+	if sug.Applied {
+		fmt.Fprintln(w, "Now using this exit node.")
+	} else {
+		fmt.Fprintf(w, "To accept this suggestion, use `tailscale set --exit-node=%v` or `tailscale exit-node suggest --set`.\n", sug.ID)
+	}
+}
+
 func hasAnyExitNodeSuggestions(peers []*ipnstate.PeerStatus) bool {
 	for _, peer := range peers {
 		if peer.HasCap(tailcfg.NodeAttrSuggestExitNode) {
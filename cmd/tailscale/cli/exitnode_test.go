@@ -4,10 +4,17 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/netip"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"tailscale.com/client/tailscale/apitype"
+	"tailscale.com/ipn"
 	"tailscale.com/ipn/ipnstate"
 	"tailscale.com/tailcfg"
 	"tailscale.com/types/key"
@@ -306,3 +313,115 @@ func TestSortByCityName(t *testing.T) {
 		t.Fatalf("sortByCityName did not order cities by alphabetical order, got %v, want %v", fc[0].Name, noLocationData)
 	}
 }
+
+func TestExitNodeSuggest(t *testing.T) {
+	loc := &tailcfg.Location{Country: "Canada", CountryCode: "CA", City: "Squamish", CityCode: "YSE"}
+	peerIP := netip.MustParseAddr("100.64.0.7")
+	newEnv := func(res apitype.ExitNodeSuggestionResponse, gotPrefs **ipn.MaskedPrefs) *exitNodeSuggestEnv {
+		return &exitNodeSuggestEnv{
+			suggest: func(context.Context) (apitype.ExitNodeSuggestionResponse, error) {
+				return res, nil
+			},
+			status: func(context.Context) (*ipnstate.Status, error) {
+				return &ipnstate.Status{
+					Peer: map[key.NodePublic]*ipnstate.PeerStatus{
+						key.NewNode().Public(): {ID: "other", TailscaleIPs: []netip.Addr{netip.MustParseAddr("100.64.0.8")}},
+						key.NewNode().Public(): {ID: "n123", TailscaleIPs: []netip.Addr{peerIP}},
+					},
+				}, nil
+			},
+			ping: func(_ context.Context, ip netip.Addr, _ tailcfg.PingType) (*ipnstate.PingResult, error) {
+				if ip != peerIP {
+					t.Errorf("pinged %v; want %v", ip, peerIP)
+				}
+				return &ipnstate.PingResult{LatencySeconds: 0.0123}, nil
+			},
+			editPrefs: func(_ context.Context, mp *ipn.MaskedPrefs) (*ipn.Prefs, error) {
+				*gotPrefs = mp
+				return &mp.Prefs, nil
+			},
+		}
+	}
+	sugRes := apitype.ExitNodeSuggestionResponse{
+		ID:       "n123",
+		Name:     "squamish-1",
+		Location: loc.View(),
+	}
+
+	t.Run("print", func(t *testing.T) {
+		var mp *ipn.MaskedPrefs
+		var buf bytes.Buffer
+		if err := newEnv(sugRes, &mp).run(context.Background(), &buf, false, false); err != nil {
+			t.Fatal(err)
+		}
+		want := strings.Join([]string{
+			"Suggested exit node: squamish-1",
+			"Location: Squamish, Canada",
+			"Latency: 12.3ms",
+			"To accept this suggestion, use `tailscale set --exit-node=n123` or `tailscale exit-node suggest --set`.",
+			"",
+		}, "\n")
+		if got := buf.String(); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+		if mp != nil {
+			t.Errorf("prefs edited without --set: %+v", mp)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		var mp *ipn.MaskedPrefs
+		var buf bytes.Buffer
+		if err := newEnv(sugRes, &mp).run(context.Background(), &buf, false, true); err != nil {
+			t.Fatal(err)
+		}
+		if mp == nil {
+			t.Fatal("prefs not edited with --set")
+		}
+		if !mp.ExitNodeIDSet || mp.ExitNodeID != "n123" {
+			t.Errorf("ExitNodeID = %q (set=%v); want n123", mp.ExitNodeID, mp.ExitNodeIDSet)
+		}
+		if !mp.ExitNodeIPSet || mp.ExitNodeIP.IsValid() {
+			t.Errorf("ExitNodeIP = %v (set=%v); want cleared", mp.ExitNodeIP, mp.ExitNodeIPSet)
+		}
+		if !strings.Contains(buf.String(), "Now using this exit node.") {
+			t.Errorf("output missing confirmation:\n%s", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var mp *ipn.MaskedPrefs
+		var buf bytes.Buffer
+		if err := newEnv(sugRes, &mp).run(context.Background(), &buf, true, true); err != nil {
+			t.Fatal(err)
+		}
+		var got exitNodeSuggestion
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+		}
+		want := exitNodeSuggestion{
+			ID:             "n123",
+			Name:           "squamish-1",
+			Location:       loc,
+			LatencySeconds: 0.0123,
+			Applied:        true,
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		var mp *ipn.MaskedPrefs
+		var buf bytes.Buffer
+		if err := newEnv(apitype.ExitNodeSuggestionResponse{}, &mp).run(context.Background(), &buf, false, true); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := buf.String(), "No exit node suggestion is available.\n"; got != want {
+			t.Errorf("got %q; want %q", got, want)
+		}
+		if mp != nil {
+			t.Errorf("prefs edited with no suggestion: %+v", mp)
+		}
+	})
+}
@@ -4,15 +4,19 @@
 package cli
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"tailscale.com/ipn"
+	"tailscale.com/ipn/ipnstate"
 	"tailscale.com/tailcfg"
 )
 
@@ -37,7 +41,7 @@ func newFunnelCommand(e *serveEnv) *ffcli.Command {
 		ShortHelp: "Turn on/off Funnel service",
 		ShortUsage: strings.Join([]string{
 			"tailscale funnel <serve-port> {on|off}",
-			"tailscale funnel status [--json]",
+			"tailscale funnel status [--json | --mappings]",
 		}, "\n"),
 		LongHelp: strings.Join([]string{
 			"Funnel allows you to publish a 'tailscale serve'",
@@ -50,11 +54,12 @@ func newFunnelCommand(e *serveEnv) *ffcli.Command {
 		Subcommands: []*ffcli.Command{
 			{
 				Name:       "status",
-				Exec:       e.runServeStatus,
-				ShortUsage: "tailscale funnel status [--json]",
+				Exec:       e.runFunnelStatus,
+				ShortUsage: "tailscale funnel status [--json | --mappings]",
 				ShortHelp:  "Show current serve/funnel status",
 				FlagSet: e.newFlags("funnel-status", func(fs *flag.FlagSet) {
 					fs.BoolVar(&e.json, "json", false, "output JSON")
+					fs.BoolVar(&e.funnelMappings, "mappings", false, "output only the handlers exposed over Funnel, as JSON")
 				}),
 			},
 		},
@@ -174,3 +179,128 @@ func printFunnelWarning(sc *ipn.ServeConfig) {
 		fmt.Fprintf(Stderr, "         run: `tailscale serve --help` to see how to configure handlers\n")
 	}
 }
+
+// funnelStatus is the output of "tailscale funnel status --mappings".
+type funnelStatus struct {
+	// Enabled is whether Funnel is on for at least one port.
+	Enabled bool
+
+	// NodeAllowed is whether the tailnet policy allows this node to use
+	// Funnel at all.
+	NodeAllowed bool
+
+	// Mappings are the handlers exposed over Funnel, sorted by port and
+	// then path.
+	Mappings []funnelMapping
+}
+
+// funnelMapping is a single handler exposed to the internet over Funnel.
+type funnelMapping struct {
+	HostPort ipn.HostPort // e.g. "node.tailnet.ts.net:443"
+	Port     uint16
+
+	// Protocol is "https", "tcp" (TLS over TCP passed through to the
+	// target), or "tls-terminated-tcp".
+	Protocol string
+
+	// Path is the mount point of the handler, for https only.
+	Path string `json:",omitempty"`
+
+	// TargetType is how the target is served: "proxy", "path", "text", or
+	// "tcp" for TCP forwarding.
+	TargetType string
+	Target     string
+}
+
+// runFunnelStatus is like runServeStatus, but with --mappings it outputs
+// only the handlers exposed over Funnel, as a funnelStatus in JSON.
+func (e *serveEnv) runFunnelStatus(ctx context.Context, args []string) error {
+	if !e.funnelMappings {
+		return e.runServeStatus(ctx, args)
+	}
+	sc, err := e.lc.GetServeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	st, err := e.getLocalClientStatusWithoutPeers(ctx)
+	if err != nil {
+		return fmt.Errorf("getting client status: %w", err)
+	}
+	j, err := json.MarshalIndent(funnelStatusOf(sc, st), "", "  ")
+	if err != nil {
+		return err
+	}
+	j = append(j, '\n')
+	e.stdout().Write(j)
+	return nil
+}
+
+// funnelStatusOf returns the Funnel status described by sc and st.
+// sc may be nil.
+func funnelStatusOf(sc *ipn.ServeConfig, st *ipnstate.Status) funnelStatus {
+	fs := funnelStatus{
+		Enabled:     sc.IsFunnelOn(),
+		NodeAllowed: st.Self != nil && st.Self.HasCap(tailcfg.NodeAttrFunnel),
+		Mappings:    []funnelMapping{},
+	}
+	if sc == nil {
+		return fs
+	}
+	for hp, on := range sc.AllowFunnel {
+		if !on {
+			continue
+		}
+		_, portStr, err := net.SplitHostPort(string(hp))
+		if err != nil {
+			continue
+		}
+		port64, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			continue
+		}
+		port := uint16(port64)
+		if h := sc.TCP[port]; h != nil && h.TCPForward != "" {
+			proto := "tcp"
+			if h.TerminateTLS != "" {
+				proto = "tls-terminated-tcp"
+			}
+			fs.Mappings = append(fs.Mappings, funnelMapping{
+				HostPort:   hp,
+				Port:       port,
+				Protocol:   proto,
+				TargetType: "tcp",
+				Target:     h.TCPForward,
+			})
+			continue
+		}
+		web := sc.Web[hp]
+		if web == nil {
+			continue
+		}
+		for path, h := range web.Handlers {
+			m := funnelMapping{
+				HostPort: hp,
+				Port:     port,
+				Protocol: "https",
+				Path:     path,
+			}
+			switch {
+			case h.Proxy != "":
+				m.TargetType, m.Target = "proxy", h.Proxy
+			case h.Path != "":
+				m.TargetType, m.Target = "path", h.Path
+			default:
+				m.TargetType, m.Target = "text", h.Text
+			}
+			fs.Mappings = append(fs.Mappings, m)
+		}
+	}
+	slices.SortFunc(fs.Mappings, func(a, b funnelMapping) int {
+		return cmp.Or(
+			cmp.Compare(a.Port, b.Port),
+			cmp.Compare(a.HostPort, b.HostPort),
+			cmp.Compare(a.Path, b.Path),
+		)
+	})
+	return fs
+}
@@ -4,6 +4,7 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
@@ -11,6 +12,7 @@
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 	"time"
@@ -28,7 +30,7 @@
 
 var netcheckCmd = &ffcli.Command{
 	Name:       "netcheck",
-	ShortUsage: "tailscale netcheck",
+	ShortUsage: "tailscale netcheck [--report-to=<url>]",
 	ShortHelp:  "Print an analysis of local network conditions",
 	Exec:       runNetcheck,
 	FlagSet: (func() *flag.FlagSet {
@@ -36,33 +38,32 @@
 		fs.StringVar(&netcheckArgs.format, "format", "", `output format; empty (for human-readable), "json" or "json-line"`)
 		fs.DurationVar(&netcheckArgs.every, "every", 0, "if non-zero, do an incremental report with the given frequency")
 		fs.BoolVar(&netcheckArgs.verbose, "verbose", false, "verbose logs")
+		fs.StringVar(&netcheckArgs.reportTo, "report-to", "", "if non-empty, an http or https URL to which each report is also POSTed as JSON")
 		return fs
 	})(),
 }
 
 var netcheckArgs struct {
-	format  string
-	every   time.Duration
-	verbose bool
+	format   string
+	every    time.Duration
+	verbose  bool
+	reportTo string
 }
 
+// netcheckReportTimeout bounds how long sending a report to the
+// --report-to URL may take.
+const netcheckReportTimeout = 10 * time.Second
+
 func runNetcheck(ctx context.Context, args []string) error {
-	logf := logger.WithPrefix(log.Printf, "portmap: ")
-	netMon, err := netmon.New(logf)
+	if u := netcheckArgs.reportTo; u != "" {
+		if pu, err := url.Parse(u); err != nil || (pu.Scheme != "http" && pu.Scheme != "https") || pu.Host == "" {
+			return fmt.Errorf("invalid --report-to URL %q; must be an http or https URL", u)
+		}
+	}
+	c, err := newNetcheckClient(netcheckArgs.verbose)
 	if err != nil {
 		return err
 	}
-	c := &netcheck.Client{
-		NetMon:      netMon,
-		PortMapper:  portmapper.NewClient(logf, netMon, nil, nil, nil),
-		UseDNSCache: false, // always resolve, don't cache
-	}
-	if netcheckArgs.verbose {
-		c.Logf = logger.WithPrefix(log.Printf, "netcheck: ")
-		c.Verbose = true
-	} else {
-		c.Logf = logger.Discard
-	}
 
 	if strings.HasPrefix(netcheckArgs.format, "json") {
 		fmt.Fprintln(Stderr, "# Warning: this JSON format is not yet considered a stable interface")
@@ -72,17 +73,9 @@ func runNetcheck(ctx context.Context, args []string) error {
 		fmt.Fprintln(Stderr, "netcheck: UDP test failure:", err)
 	}
 
-	dm, err := localClient.CurrentDERPMap(ctx)
-	noRegions := dm != nil && len(dm.Regions) == 0
-	if noRegions {
-		log.Printf("No DERP map from tailscaled; using default.")
-	}
-	if err != nil || noRegions {
-		hc := &http.Client{Transport: tlsdial.NewTransport()}
-		dm, err = prodDERPMap(ctx, hc)
-		if err != nil {
-			return err
-		}
+	dm, err := netcheckDERPMap(ctx)
+	if err != nil {
+		return err
 	}
 	for {
 		t0 := time.Now()
@@ -97,6 +90,9 @@ func runNetcheck(ctx context.Context, args []string) error {
 		if err := printReport(dm, report); err != nil {
 			return err
 		}
+		if netcheckArgs.reportTo != "" {
+			sendNetcheckReport(ctx, Stderr, http.DefaultClient, netcheckArgs.reportTo, report)
+		}
 		if netcheckArgs.every == 0 {
 			return nil
 		}
@@ -104,6 +100,77 @@ func runNetcheck(ctx context.Context, args []string) error {
 	}
 }
 
+// newNetcheckClient returns a netcheck.Client for use by the CLI.
+// If verbose is set, the client logs its progress.
+func newNetcheckClient(verbose bool) (*netcheck.Client, error) {
+	logf := logger.WithPrefix(log.Printf, "portmap: ")
+	netMon, err := netmon.New(logf)
+	if err != nil {
+		return nil, err
+	}
+	c := &netcheck.Client{
+		NetMon:      netMon,
+		PortMapper:  portmapper.NewClient(logf, netMon, nil, nil, nil),
+		UseDNSCache: false, // always resolve, don't cache
+	}
+	if verbose {
+		c.Logf = logger.WithPrefix(log.Printf, "netcheck: ")
+		c.Verbose = true
+	} else {
+		c.Logf = logger.Discard
+	}
+	return c, nil
+}
+
+// netcheckDERPMap returns the DERP map to use for netcheck: the one from
+// tailscaled if available, or else the default production one.
+func netcheckDERPMap(ctx context.Context) (*tailcfg.DERPMap, error) {
+	dm, err := localClient.CurrentDERPMap(ctx)
+	noRegions := dm != nil && len(dm.Regions) == 0
+	if noRegions {
+		log.Printf("No DERP map from tailscaled; using default.")
+	}
+	if err != nil || noRegions {
+		hc := &http.Client{Transport: tlsdial.NewTransport()}
+		dm, err = prodDERPMap(ctx, hc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dm, nil
+}
+
+// sendNetcheckReport POSTs report as JSON to reportURL using hc. Failing to
+// send the report is not fatal: a warning is written to warnw instead.
+func sendNetcheckReport(ctx context.Context, warnw io.Writer, hc *http.Client, reportURL string, report *netcheck.Report) {
+	if err := postNetcheckReport(ctx, hc, reportURL, report); err != nil {
+		fmt.Fprintf(warnw, "netcheck: warning: failed to send report to %s: %v\n", reportURL, err)
+	}
+}
+
+func postNetcheckReport(ctx context.Context, hc *http.Client, reportURL string, report *netcheck.Report) error {
+	j, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, netcheckReportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", reportURL, bytes.NewReader(j))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return nil
+}
+
 func printReport(dm *tailcfg.DERPMap, report *netcheck.Report) error {
 	var j []byte
 	var err error
@@ -0,0 +1,73 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tailscale.com/net/netcheck"
+)
+
+func TestSendNetcheckReport(t *testing.T) {
+	report := &netcheck.Report{
+		UDP:           true,
+		GlobalV4:      "1.2.3.4:5678",
+		PreferredDERP: 1,
+		RegionLatency: map[int]time.Duration{1: 10 * time.Millisecond},
+	}
+
+	var gotType string
+	var gotBody []byte
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "want POST", http.StatusMethodNotAllowed)
+			return
+		}
+		gotType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer collector.Close()
+
+	var warn bytes.Buffer
+	sendNetcheckReport(context.Background(), &warn, collector.Client(), collector.URL, report)
+	if warn.Len() != 0 {
+		t.Errorf("unexpected warning: %s", warn.String())
+	}
+	if gotType != "application/json" {
+		t.Errorf("Content-Type = %q; want application/json", gotType)
+	}
+	var got netcheck.Report
+	if err := json.Unmarshal(gotBody, &got); err != nil {
+		t.Fatalf("decoding posted report %q: %v", gotBody, err)
+	}
+	if got.GlobalV4 != report.GlobalV4 || !got.UDP || got.PreferredDERP != 1 || got.RegionLatency[1] != 10*time.Millisecond {
+		t.Errorf("posted report = %+v; want %+v", got, report)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	warn.Reset()
+	sendNetcheckReport(context.Background(), &warn, failing.Client(), failing.URL, report)
+	if !strings.Contains(warn.String(), "warning") || !strings.Contains(warn.String(), "500") {
+		t.Errorf("failed POST: got warning %q; want one mentioning the 500 status", warn.String())
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	warn.Reset()
+	sendNetcheckReport(context.Background(), &warn, http.DefaultClient, closed.URL, report)
+	if !strings.Contains(warn.String(), "failed to send report") {
+		t.Errorf("unreachable collector: got warning %q", warn.String())
+	}
+}
@@ -105,7 +105,7 @@ func newServeLegacyCommand(e *serveEnv) *ffcli.Command {
 				Name:      "reset",
 				Exec:      e.runServeReset,
 				ShortHelp: "Reset current serve/funnel config",
-				FlagSet:   e.newFlags("serve-reset", nil),
+				FlagSet:   e.newServeResetFlags(),
 			},
 		},
 	}
@@ -128,6 +128,14 @@ func (e *serveEnv) newFlags(name string, setup func(fs *flag.FlagSet)) *flag.Fla
 	return fs
 }
 
+// newServeResetFlags returns the flags for the "reset" subcommand.
+func (e *serveEnv) newServeResetFlags() *flag.FlagSet {
+	return e.newFlags("serve-reset", func(fs *flag.FlagSet) {
+		fs.BoolVar(&e.yes, "yes", false, "reset without an interactive prompt (default false)")
+		fs.BoolVar(&e.json, "json", false, "output the cleared config as JSON")
+	})
+}
+
 // localServeClient is an interface conforming to the subset of
 // tailscale.LocalClient. It includes only the methods used by the
 // serve command.
@@ -150,7 +158,8 @@ type localServeClient interface {
 // It also contains the flags, as registered with newServeCommand.
 type serveEnv struct {
 	// v1 flags
-	json bool // output JSON (status only for now)
+	json           bool // output JSON (status and reset only for now)
+	funnelMappings bool // output only Funnel mappings as JSON (funnel status only)
 
 	// v2 specific flags
 	bg               bool      // background mode
@@ -168,6 +177,7 @@ type serveEnv struct {
 	testFlagOut io.Writer
 	testStdout  io.Writer
 	testStderr  io.Writer
+	testStdin   io.Reader
 }
 
 // getSelfDNSName returns the DNS name of the current node.
@@ -614,7 +624,7 @@ func (e *serveEnv) runServeStatus(ctx context.Context, args []string) error {
 		return nil
 	}
 	printFunnelStatus(ctx)
-	if sc == nil || (len(sc.TCP) == 0 && len(sc.Web) == 0 && len(sc.AllowFunnel) == 0) {
+	if serveConfigIsEmpty(sc) {
 		printf("No serve config\n")
 		return nil
 	}
@@ -732,16 +742,48 @@ func elipticallyTruncate(s string, max int) string {
 	return s[:max-3] + "..."
 }
 
-// runServeReset clears out the current serve config.
+// runServeReset clears out the current serve config. Unless --yes is
+// given, the user is asked on stderr to confirm before a non-empty config
+// is cleared, and an error is returned if they don't. With --json, the
+// cleared config is printed for auditing.
 //
 // Usage:
-//   - tailscale serve reset
+//   - tailscale serve reset [--yes] [--json]
 func (e *serveEnv) runServeReset(ctx context.Context, args []string) error {
 	if len(args) != 0 {
 		return flag.ErrHelp
 	}
-	sc := new(ipn.ServeConfig)
-	return e.lc.SetServeConfig(ctx, sc)
+	old, err := e.lc.GetServeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if !e.yes && !serveConfigIsEmpty(old) {
+		// Prompt on stderr so that it doesn't mix with --json output.
+		if !promptYesNoFrom(e.stdin(), e.stderr(), "This will remove all serve and funnel configuration. Continue?") {
+			return errors.New("reset aborted")
+		}
+	}
+	if err := e.lc.SetServeConfig(ctx, new(ipn.ServeConfig)); err != nil {
+		return err
+	}
+	if e.json {
+		if old == nil {
+			old = new(ipn.ServeConfig)
+		}
+		j, err := json.MarshalIndent(old, "", "  ")
+		if err != nil {
+			return err
+		}
+		j = append(j, '\n')
+		e.stdout().Write(j)
+	}
+	return nil
+}
+
+// serveConfigIsEmpty reports whether sc has no TCP, Web, or Funnel
+// configuration.
+func serveConfigIsEmpty(sc *ipn.ServeConfig) bool {
+	return sc == nil || (len(sc.TCP) == 0 && len(sc.Web) == 0 && len(sc.AllowFunnel) == 0)
 }
 
 // parseServePort parses a port number from a string and returns it as a
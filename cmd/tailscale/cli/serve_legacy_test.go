@@ -286,7 +286,7 @@ type step struct {
 		want:    nil, // nothing to save
 	})
 	add(step{ // try resetting using reset command
-		command: cmd("reset"),
+		command: cmd("reset --yes"),
 		want:    &ipn.ServeConfig{},
 	})
 	add(step{
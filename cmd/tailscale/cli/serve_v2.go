@@ -105,6 +105,10 @@ func newServeV2Command(e *serveEnv, subcmd serveMode) *ffcli.Command {
 	}
 
 	info := infoMap[subcmd]
+	statusExec := e.runServeStatus
+	if subcmd == funnel {
+		statusExec = e.runFunnelStatus
+	}
 
 	return &ffcli.Command{
 		Name:      info.Name,
@@ -112,7 +116,7 @@ func newServeV2Command(e *serveEnv, subcmd serveMode) *ffcli.Command {
 		ShortUsage: strings.Join([]string{
 			fmt.Sprintf("tailscale %s <target>", info.Name),
 			fmt.Sprintf("tailscale %s status [--json]", info.Name),
-			fmt.Sprintf("tailscale %s reset", info.Name),
+			fmt.Sprintf("tailscale %s reset [--yes] [--json]", info.Name),
 		}, "\n"),
 		LongHelp: info.LongHelp + fmt.Sprintf(strings.TrimSpace(serveHelpCommon), info.Name),
 		Exec:     e.runServeCombined(subcmd),
@@ -133,18 +137,21 @@ func newServeV2Command(e *serveEnv, subcmd serveMode) *ffcli.Command {
 			{
 				Name:       "status",
 				ShortUsage: "tailscale " + info.Name + " status [--json]",
-				Exec:       e.runServeStatus,
+				Exec:       statusExec,
 				ShortHelp:  "View current " + info.Name + " configuration",
 				FlagSet: e.newFlags("serve-status", func(fs *flag.FlagSet) {
 					fs.BoolVar(&e.json, "json", false, "output JSON")
+					if subcmd == funnel {
+						fs.BoolVar(&e.funnelMappings, "mappings", false, "output only the handlers exposed over Funnel, as JSON")
+					}
 				}),
 			},
 			{
 				Name:       "reset",
-				ShortUsage: "tailscale " + info.Name + " reset",
+				ShortUsage: "tailscale " + info.Name + " reset [--yes] [--json]",
 				ShortHelp:  "Reset current " + info.Name + " config",
 				Exec:       e.runServeReset,
-				FlagSet:    e.newFlags("serve-reset", nil),
+				FlagSet:    e.newServeResetFlags(),
 			},
 		},
 	}
@@ -760,7 +767,7 @@ func (e *serveEnv) removeWebServe(sc *ipn.ServeConfig, dnsName string, srvPort u
 
 	if len(mounts) > 1 {
 		msg := fmt.Sprintf("Are you sure you want to delete %d handlers under port %s?", len(mounts), portStr)
-		if !e.yes && !promptYesNo(msg) {
+		if !e.yes && !e.promptYesNo(msg) {
 			return nil
 		}
 	}
@@ -832,3 +839,16 @@ func (e *serveEnv) stderr() io.Writer {
 	}
 	return Stderr
 }
+
+func (e *serveEnv) stdin() io.Reader {
+	if e.testStdin != nil {
+		return e.testStdin
+	}
+	return os.Stdin
+}
+
+// promptYesNo is like the package-level promptYesNo, but uses the
+// serveEnv's stdin and stdout, which may be faked out in tests.
+func (e *serveEnv) promptYesNo(msg string) bool {
+	return promptYesNoFrom(e.stdin(), e.stdout(), msg)
+}
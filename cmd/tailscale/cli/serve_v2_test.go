@@ -333,7 +333,7 @@ type group struct {
 					},
 				},
 				{
-					command: cmd("serve reset"),
+					command: cmd("serve reset --yes"),
 					want:    &ipn.ServeConfig{},
 				},
 			},
@@ -682,7 +682,7 @@ type group struct {
 					},
 				},
 				{ // reset and do opposite
-					command: cmd("serve reset"),
+					command: cmd("serve reset --yes"),
 					want:    &ipn.ServeConfig{},
 				},
 				{ // a file without a trailing slash mount point
@@ -1041,6 +1041,81 @@ func TestSrcTypeFromFlags(t *testing.T) {
 	}
 }
 
+func TestServeReset(t *testing.T) {
+	initial := &ipn.ServeConfig{
+		TCP: map[uint16]*ipn.TCPPortHandler{443: {HTTPS: true}},
+		Web: map[ipn.HostPort]*ipn.WebServerConfig{
+			"foo.test.ts.net:443": {Handlers: map[string]*ipn.HTTPHandler{
+				"/": {Proxy: "http://127.0.0.1:3000"},
+			}},
+		},
+	}
+	tests := []struct {
+		name        string
+		args        string
+		stdin       string
+		wantPrompt  bool
+		wantErr     bool
+		wantCleared bool
+		wantJSON    bool
+	}{
+		{name: "prompt-declined", args: "reset", stdin: "n\n", wantPrompt: true, wantErr: true},
+		{name: "prompt-eof", args: "reset", stdin: "", wantPrompt: true, wantErr: true},
+		{name: "prompt-accepted", args: "reset", stdin: "y\n", wantPrompt: true, wantCleared: true},
+		{name: "prompt-accepted-json", args: "reset --json", stdin: "y\n", wantPrompt: true, wantCleared: true, wantJSON: true},
+		{name: "yes", args: "reset --yes", wantCleared: true},
+		{name: "json", args: "reset --yes --json", wantCleared: true, wantJSON: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := &fakeLocalServeClient{config: initial.Clone()}
+			var stdout, stderr, flagOut bytes.Buffer
+			e := &serveEnv{
+				lc:          lc,
+				testFlagOut: &flagOut,
+				testStdout:  &stdout,
+				testStderr:  &stderr,
+				testStdin:   strings.NewReader(tt.stdin),
+			}
+			cmd := newServeV2Command(e, serve)
+			err := cmd.ParseAndRun(context.Background(), strings.Fields(tt.args))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v; want error: %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "reset aborted") {
+				t.Errorf("err = %v; want reset aborted", err)
+			}
+			if gotPrompt := strings.Contains(stderr.String(), "[y/n]"); gotPrompt != tt.wantPrompt {
+				t.Errorf("prompted on stderr = %v; want %v", gotPrompt, tt.wantPrompt)
+			}
+			if strings.Contains(stdout.String(), "[y/n]") {
+				t.Errorf("prompt written to stdout: %q", stdout.String())
+			}
+			if tt.wantCleared {
+				if !reflect.DeepEqual(lc.config, &ipn.ServeConfig{}) {
+					t.Errorf("config not cleared: %+v", lc.config)
+				}
+			} else {
+				if lc.setCount != 0 {
+					t.Errorf("SetServeConfig called %d times; want 0", lc.setCount)
+				}
+				if !reflect.DeepEqual(lc.config, initial) {
+					t.Errorf("config changed: %+v", lc.config)
+				}
+			}
+			if tt.wantJSON {
+				var got ipn.ServeConfig
+				if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+					t.Fatalf("invalid JSON output %q: %v", stdout.Bytes(), err)
+				}
+				if !reflect.DeepEqual(&got, initial) {
+					t.Errorf("JSON output = %+v; want %+v", &got, initial)
+				}
+			}
+		})
+	}
+}
+
 func TestCleanURLPath(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1287,3 +1362,111 @@ func exactErrMsg(want error) func(error) string {
 		return fmt.Sprintf("\ngot:  %v\nwant: %v\n", got, want)
 	}
 }
+
+func TestFunnelStatusMappings(t *testing.T) {
+	sc := &ipn.ServeConfig{
+		TCP: map[uint16]*ipn.TCPPortHandler{
+			443:   {HTTPS: true},
+			8443:  {TCPForward: "127.0.0.1:5432", TerminateTLS: "foo.test.ts.net"},
+			10000: {HTTPS: true},
+		},
+		Web: map[ipn.HostPort]*ipn.WebServerConfig{
+			"foo.test.ts.net:443": {Handlers: map[string]*ipn.HTTPHandler{
+				"/":       {Proxy: "http://127.0.0.1:3000"},
+				"/static": {Path: "/var/www"},
+				"/hello":  {Text: "hi"},
+			}},
+			"foo.test.ts.net:10000": {Handlers: map[string]*ipn.HTTPHandler{
+				"/": {Proxy: "http://127.0.0.1:4000"}, // tailnet only
+			}},
+		},
+		AllowFunnel: map[ipn.HostPort]bool{
+			"foo.test.ts.net:443":  true,
+			"foo.test.ts.net:8443": true,
+		},
+	}
+	lc := &fakeLocalServeClient{config: sc}
+	var stdout bytes.Buffer
+	e := &serveEnv{
+		lc:         lc,
+		testStdout: &stdout,
+		testStderr: &stdout,
+	}
+	// Plain --json keeps printing the full serve config.
+	if err := newServeV2Command(e, funnel).ParseAndRun(context.Background(), []string{"status", "--json"}); err != nil {
+		t.Fatal(err)
+	}
+	var gotSC ipn.ServeConfig
+	if err := json.Unmarshal(stdout.Bytes(), &gotSC); err != nil {
+		t.Fatalf("status --json output is not a ServeConfig: %v\n%s", err, stdout.Bytes())
+	}
+	if !reflect.DeepEqual(&gotSC, sc) {
+		t.Errorf("status --json = %+v; want %+v", gotSC, sc)
+	}
+
+	e = &serveEnv{
+		lc:         lc,
+		testStdout: &stdout,
+		testStderr: &stdout,
+	}
+	stdout.Reset()
+	if err := newServeV2Command(e, funnel).ParseAndRun(context.Background(), []string{"status", "--mappings"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "Enabled": true,
+  "NodeAllowed": true,
+  "Mappings": [
+    {
+      "HostPort": "foo.test.ts.net:443",
+      "Port": 443,
+      "Protocol": "https",
+      "Path": "/",
+      "TargetType": "proxy",
+      "Target": "http://127.0.0.1:3000"
+    },
+    {
+      "HostPort": "foo.test.ts.net:443",
+      "Port": 443,
+      "Protocol": "https",
+      "Path": "/hello",
+      "TargetType": "text",
+      "Target": "hi"
+    },
+    {
+      "HostPort": "foo.test.ts.net:443",
+      "Port": 443,
+      "Protocol": "https",
+      "Path": "/static",
+      "TargetType": "path",
+      "Target": "/var/www"
+    },
+    {
+      "HostPort": "foo.test.ts.net:8443",
+      "Port": 8443,
+      "Protocol": "tls-terminated-tcp",
+      "TargetType": "tcp",
+      "Target": "127.0.0.1:5432"
+    }
+  ]
+}
+`
+	if got := stdout.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// With Funnel off, the mappings are empty.
+	lc.config = &ipn.ServeConfig{}
+	stdout.Reset()
+	if err := newServeV2Command(e, funnel).ParseAndRun(context.Background(), []string{"status", "--mappings"}); err != nil {
+		t.Fatal(err)
+	}
+	var got funnelStatus
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Enabled || !got.NodeAllowed || got.Mappings == nil || len(got.Mappings) != 0 {
+		t.Errorf("funnel off: got %+v", got)
+	}
+}
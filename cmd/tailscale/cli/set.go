@@ -5,11 +5,14 @@
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"net/netip"
 	"os/exec"
+	"reflect"
 	"strings"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
@@ -61,6 +64,7 @@ type setArgsT struct {
 	snat                   bool
 	statefulFiltering      bool
 	netfilterMode          string
+	json                   bool // report pref changes in JSON format
 }
 
 func newSetFlagSet(goos string, setArgs *setArgsT) *flag.FlagSet {
@@ -81,6 +85,7 @@ func newSetFlagSet(goos string, setArgs *setArgsT) *flag.FlagSet {
 	setf.BoolVar(&setArgs.updateApply, "auto-update", false, "automatically update to the latest available version")
 	setf.BoolVar(&setArgs.postureChecking, "posture-checking", false, hidden+"allow management plane to gather device posture information")
 	setf.BoolVar(&setArgs.runWebClient, "webclient", false, "expose the web interface for managing this node over Tailscale at port 5252")
+	setf.BoolVar(&setArgs.json, "json", false, "report which preferences were changed in JSON format")
 
 	ffcomplete.Flag(setf, "exit-node", func(args []string) ([]string, ffcomplete.ShellCompDirective, error) {
 		st, err := localClient.Status(context.Background())
@@ -179,7 +184,9 @@ func runSet(ctx context.Context, args []string) (retErr error) {
 
 	warnOnAdvertiseRouts(ctx, &maskedPrefs.Prefs)
 	var advertiseExitNodeSet, advertiseRoutesSet bool
+	var setFlags []string
 	setFlagSet.Visit(func(f *flag.Flag) {
+		setFlags = append(setFlags, f.Name)
 		updateMaskedPrefsFromUpOrSetFlag(maskedPrefs, f.Name)
 		switch f.Name {
 		case "advertise-exit-node":
@@ -227,24 +234,116 @@ func runSet(ctx context.Context, args []string) (retErr error) {
 			}
 		}
 	}
-	checkPrefs := curPrefs.Clone()
-	checkPrefs.ApplyEdits(maskedPrefs)
-	if err := localClient.CheckPrefs(ctx, checkPrefs); err != nil {
+	if err := applySetPrefs(ctx, &localClient, Stdout, curPrefs, maskedPrefs, setFlags, setArgs.json); err != nil {
 		return err
 	}
 
-	_, err = localClient.EditPrefs(ctx, maskedPrefs)
+	if setArgs.runWebClient && len(st.TailscaleIPs) > 0 && !setArgs.json {
+		printf("\nWeb interface now running at %s:%d", st.TailscaleIPs[0], web.ListenPort)
+	}
+
+	return nil
+}
+
+// setPrefsClient is the subset of the LocalClient used to apply the edits
+// made by "tailscale set", so that it can be faked out in tests.
+type setPrefsClient interface {
+	CheckPrefs(context.Context, *ipn.Prefs) error
+	EditPrefs(context.Context, *ipn.MaskedPrefs) (*ipn.Prefs, error)
+}
+
+// setPrefChange describes the effect of "tailscale set" on a single pref.
+type setPrefChange struct {
+	Flag    string // flag that set the pref, e.g. "accept-routes"
+	Pref    string // ipn.Prefs field path, e.g. "RouteAll" or "AutoUpdate.Check"
+	Old     any    // value before the edit
+	New     any    // value after the edit
+	Changed bool   // whether Old and New differ
+}
+
+// applySetPrefs validates and applies mp on top of curPrefs, then reports
+// to w which of the prefs mentioned by setFlags were actually changed and
+// which were already set to the requested value.
+func applySetPrefs(ctx context.Context, lc setPrefsClient, w io.Writer, curPrefs *ipn.Prefs, mp *ipn.MaskedPrefs, setFlags []string, asJSON bool) error {
+	checkPrefs := curPrefs.Clone()
+	checkPrefs.ApplyEdits(mp)
+	if err := lc.CheckPrefs(ctx, checkPrefs); err != nil {
+		return err
+	}
+	newPrefs, err := lc.EditPrefs(ctx, mp)
 	if err != nil {
 		return err
 	}
 
-	if setArgs.runWebClient && len(st.TailscaleIPs) > 0 {
-		printf("\nWeb interface now running at %s:%d", st.TailscaleIPs[0], web.ListenPort)
+	changes := diffSetPrefs(setFlags, curPrefs, newPrefs)
+	if asJSON {
+		if changes == nil {
+			changes = []setPrefChange{}
+		}
+		j, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\n", j)
+		return nil
+	}
+	for _, c := range changes {
+		if c.Changed {
+			fmt.Fprintf(w, "%s: changed from %v to %v\n", c.Pref, c.Old, c.New)
+		} else {
+			fmt.Fprintf(w, "%s: unchanged, already %v\n", c.Pref, c.New)
+		}
 	}
-
 	return nil
 }
 
+// diffSetPrefs compares the prefs corresponding to setFlags between
+// oldPrefs and newPrefs. Prefs set by more than one flag are reported once,
+// under the first such flag.
+func diffSetPrefs(setFlags []string, oldPrefs, newPrefs *ipn.Prefs) []setPrefChange {
+	var changes []setPrefChange
+	seen := map[string]bool{}
+	for _, flagName := range setFlags {
+		if preflessFlag(flagName) {
+			continue
+		}
+		for _, pref := range prefsOfFlag[flagName] {
+			if seen[pref] {
+				continue
+			}
+			seen[pref] = true
+			oldv, newv := prefField(oldPrefs, pref), prefField(newPrefs, pref)
+			changes = append(changes, setPrefChange{
+				Flag:    flagName,
+				Pref:    pref,
+				Old:     oldv.Interface(),
+				New:     newv.Interface(),
+				Changed: !prefValuesEqual(oldv, newv),
+			})
+		}
+	}
+	return changes
+}
+
+// prefField returns the field of p at the dot-separated path pref, as
+// listed in prefsOfFlag.
+func prefField(p *ipn.Prefs, pref string) reflect.Value {
+	f := reflect.ValueOf(p).Elem()
+	for _, name := range strings.Split(pref, ".") {
+		f = f.FieldByName(name)
+	}
+	return f
+}
+
+// prefValuesEqual reports whether a and b are equal, treating nil and empty
+// slices as equal.
+func prefValuesEqual(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
 // calcAdvertiseRoutesForSet returns the new value for Prefs.AdvertiseRoutes based on the
 // current value, the flags passed to "tailscale set".
 // advertiseExitNodeSet is whether the --advertise-exit-node flag was set.
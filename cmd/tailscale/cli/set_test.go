@@ -4,6 +4,9 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"net/netip"
 	"reflect"
 	"testing"
@@ -129,3 +132,106 @@ func TestCalcAdvertiseRoutesForSet(t *testing.T) {
 		})
 	}
 }
+
+type fakeSetPrefsClient struct {
+	prefs *ipn.Prefs
+}
+
+func (c *fakeSetPrefsClient) CheckPrefs(context.Context, *ipn.Prefs) error { return nil }
+
+func (c *fakeSetPrefsClient) EditPrefs(_ context.Context, mp *ipn.MaskedPrefs) (*ipn.Prefs, error) {
+	c.prefs.ApplyEdits(mp)
+	return c.prefs.Clone(), nil
+}
+
+func TestApplySetPrefs(t *testing.T) {
+	acceptRoutes := func() *ipn.MaskedPrefs {
+		return &ipn.MaskedPrefs{
+			Prefs:       ipn.Prefs{RouteAll: true},
+			RouteAllSet: true,
+		}
+	}
+	tests := []struct {
+		name     string
+		routeAll bool // current value of RouteAll
+		json     bool
+		want     string
+	}{
+		{
+			name: "unset",
+			want: "RouteAll: changed from false to true\n",
+		},
+		{
+			name:     "already-set",
+			routeAll: true,
+			want:     "RouteAll: unchanged, already true\n",
+		},
+		{
+			name: "json-unset",
+			json: true,
+			want: `[
+  {
+    "Flag": "accept-routes",
+    "Pref": "RouteAll",
+    "Old": false,
+    "New": true,
+    "Changed": true
+  }
+]
+`,
+		},
+		{
+			name:     "json-already-set",
+			routeAll: true,
+			json:     true,
+			want: `[
+  {
+    "Flag": "accept-routes",
+    "Pref": "RouteAll",
+    "Old": true,
+    "New": true,
+    "Changed": false
+  }
+]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur := ipn.NewPrefs()
+			cur.RouteAll = tt.routeAll
+			lc := &fakeSetPrefsClient{prefs: cur.Clone()}
+			var buf bytes.Buffer
+			if err := applySetPrefs(context.Background(), lc, &buf, cur, acceptRoutes(), []string{"accept-routes", "json"}, tt.json); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if !lc.prefs.RouteAll {
+				t.Error("RouteAll not applied")
+			}
+		})
+	}
+}
+
+func TestDiffSetPrefs(t *testing.T) {
+	old := ipn.NewPrefs()
+	old.AdvertiseRoutes = nil
+	cur := old.Clone()
+	cur.AdvertiseRoutes = []netip.Prefix{}
+	cur.Hostname = "foo"
+
+	got := diffSetPrefs([]string{"advertise-exit-node", "advertise-routes", "hostname"}, old, cur)
+	var summary []string
+	for _, c := range got {
+		summary = append(summary, fmt.Sprintf("%s/%s/%v", c.Flag, c.Pref, c.Changed))
+	}
+	want := []string{
+		"advertise-exit-node/AdvertiseRoutes/false", // nil and empty are equal; reported once
+		"hostname/Hostname/true",
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("got %q; want %q", summary, want)
+	}
+}
@@ -7,6 +7,7 @@
 	"bytes"
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/netip"
@@ -26,7 +27,7 @@
 
 var sshCmd = &ffcli.Command{
 	Name:       "ssh",
-	ShortUsage: "tailscale ssh [user@]<host> [args...]",
+	ShortUsage: "tailscale ssh [--identity-file=<file>] [--no-agent | --forward-agent] [user@]<host> [args...]",
 	ShortHelp:  "SSH to a Tailscale machine",
 	LongHelp: strings.TrimSpace(`
 
@@ -43,8 +44,46 @@
   system 'ssh' command that connects via a pipe through tailscaled.
 * It automatically checks the destination server's SSH host key against the
   node's SSH host key as advertised via the Tailscale coordination server.
+
+Flags must come before the destination; any arguments after it are passed
+to the remote command.
 `),
 	Exec: runSSH,
+	FlagSet: (func() *flag.FlagSet {
+		fs := newFlagSet("ssh")
+		fs.StringVar(&sshArgs.identityFile, "identity-file", "", "authenticate with the private key in this file")
+		fs.BoolVar(&sshArgs.noAgent, "no-agent", false, "don't use keys from the SSH agent")
+		fs.BoolVar(&sshArgs.forwardAgent, "forward-agent", false, "forward the SSH agent connection to the remote host")
+		return fs
+	})(),
+}
+
+var sshArgs struct {
+	identityFile string // if non-empty, private key file to authenticate with
+	noAgent      bool   // don't use the SSH agent
+	forwardAgent bool   // forward the SSH agent to the remote host
+}
+
+// sshAuthArgv returns the arguments to the system 'ssh' command that
+// implement the authentication flags of "tailscale ssh".
+func sshAuthArgv(identityFile string, noAgent, forwardAgent bool) ([]string, error) {
+	if noAgent && forwardAgent {
+		return nil, errors.New("--no-agent and --forward-agent are mutually exclusive")
+	}
+	var argv []string
+	if identityFile != "" {
+		argv = append(argv,
+			"-o", fmt.Sprintf("IdentityFile %q", identityFile),
+			"-o", "IdentitiesOnly yes",
+		)
+	}
+	if noAgent {
+		argv = append(argv, "-o", "IdentityAgent none")
+	}
+	if forwardAgent {
+		argv = append(argv, "-o", "ForwardAgent yes")
+	}
+	return argv, nil
 }
 
 func runSSH(ctx context.Context, args []string) error {
@@ -54,6 +93,10 @@ func runSSH(ctx context.Context, args []string) error {
 	if len(args) == 0 {
 		return errors.New("usage: tailscale ssh [user@]<host>")
 	}
+	authArgv, err := sshAuthArgv(sshArgs.identityFile, sshArgs.noAgent, sshArgs.forwardAgent)
+	if err != nil {
+		return err
+	}
 	arg, argRest := args[0], args[1:]
 	username, host, ok := strings.Cut(arg, "@")
 	if !ok {
@@ -105,6 +148,7 @@ func runSSH(ctx context.Context, args []string) error {
 		"-o", "StrictHostKeyChecking yes",
 		"-o", "CanonicalizeHostname no", // https://github.com/tailscale/tailscale/issues/10348
 	)
+	argv = append(argv, authArgv...)
 
 	// MagicDNS is usually working on macOS anyway and they're not in userspace
 	// mode, so 'nc' isn't very useful.
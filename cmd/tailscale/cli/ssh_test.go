@@ -0,0 +1,75 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSSHAuthFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantArgv []string
+		wantErr  bool
+		wantRest []string
+	}{
+		{
+			name:     "none",
+			args:     []string{"host"},
+			wantRest: []string{"host"},
+		},
+		{
+			name: "identity-file",
+			args: []string{"--identity-file=/home/me/.ssh/id_ed25519", "me@host", "uptime"},
+			wantArgv: []string{
+				"-o", `IdentityFile "/home/me/.ssh/id_ed25519"`,
+				"-o", "IdentitiesOnly yes",
+			},
+			wantRest: []string{"me@host", "uptime"},
+		},
+		{
+			name:     "no-agent",
+			args:     []string{"--no-agent", "host"},
+			wantArgv: []string{"-o", "IdentityAgent none"},
+			wantRest: []string{"host"},
+		},
+		{
+			name:     "forward-agent",
+			args:     []string{"--forward-agent", "host", "--no-agent"},
+			wantArgv: []string{"-o", "ForwardAgent yes"},
+			wantRest: []string{"host", "--no-agent"}, // after the host, passed to the remote command
+		},
+		{
+			name:    "conflict",
+			args:    []string{"--no-agent", "--forward-agent", "host"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldArgs := sshArgs
+			defer func() { sshArgs = oldArgs }()
+
+			fs := sshCmd.FlagSet
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			argv, err := sshAuthArgv(sshArgs.identityFile, sshArgs.noAgent, sshArgs.forwardAgent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v; want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !slices.Equal(argv, tt.wantArgv) {
+				t.Errorf("argv = %q; want %q", argv, tt.wantArgv)
+			}
+			if rest := fs.Args(); !slices.Equal(rest, tt.wantRest) {
+				t.Errorf("remaining args = %q; want %q", rest, tt.wantRest)
+			}
+		})
+	}
+}
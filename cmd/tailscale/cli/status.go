@@ -15,6 +15,7 @@
 	"net/http"
 	"net/netip"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -29,7 +30,7 @@
 
 var statusCmd = &ffcli.Command{
 	Name:       "status",
-	ShortUsage: "tailscale status [--active] [--web] [--json]",
+	ShortUsage: "tailscale status [--active] [--web] [--json] [--sort=<field>]",
 	ShortHelp:  "Show state of tailscaled and its connections",
 	LongHelp: strings.TrimSpace(`
 
@@ -45,6 +46,9 @@
 (and be sure to select branch/tag that corresponds to the version
  of Tailscale you're running)
 
+When --sort is combined with --json, the output is instead a JSON array of
+peers in the requested order.
+
 `),
 	Exec: runStatus,
 	FlagSet: (func() *flag.FlagSet {
@@ -56,6 +60,7 @@
 		fs.BoolVar(&statusArgs.peers, "peers", true, "show status of peers")
 		fs.StringVar(&statusArgs.listen, "listen", "127.0.0.1:8384", "listen address for web mode; use port 0 for automatic")
 		fs.BoolVar(&statusArgs.browser, "browser", true, "Open a browser in web mode")
+		fs.StringVar(&statusArgs.sort, "sort", "", "order peers by field: hostname, ip, lastseen, rx, or tx (lastseen, rx, and tx sort in descending order)")
 		return fs
 	})(),
 }
@@ -68,12 +73,70 @@
 	active  bool   // in CLI mode, filter output to only peers with active sessions
 	self    bool   // in CLI mode, show status of local machine
 	peers   bool   // in CLI mode, show status of peer machines
+	sort    string // if non-empty, the field to order peers by; see peerSortFuncs
+}
+
+// peerSortFuncs maps the values accepted by "tailscale status --sort" to
+// comparison functions for peers.
+var peerSortFuncs = map[string]func(a, b *ipnstate.PeerStatus) int{
+	"hostname": func(a, b *ipnstate.PeerStatus) int {
+		return cmp.Compare(a.HostName, b.HostName)
+	},
+	"ip": func(a, b *ipnstate.PeerStatus) int {
+		switch {
+		case len(a.TailscaleIPs) == 0 || len(b.TailscaleIPs) == 0:
+			// Peers without an IP sort last.
+			return cmp.Compare(len(b.TailscaleIPs), len(a.TailscaleIPs))
+		default:
+			return a.TailscaleIPs[0].Compare(b.TailscaleIPs[0])
+		}
+	},
+	"lastseen": func(a, b *ipnstate.PeerStatus) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	},
+	"rx": func(a, b *ipnstate.PeerStatus) int {
+		return cmp.Compare(b.RxBytes, a.RxBytes)
+	},
+	"tx": func(a, b *ipnstate.PeerStatus) int {
+		return cmp.Compare(b.TxBytes, a.TxBytes)
+	},
+}
+
+// peerSortFunc returns the comparison function for the --sort value field.
+// It returns a nil function and no error for the empty field.
+func peerSortFunc(field string) (func(a, b *ipnstate.PeerStatus) int, error) {
+	if field == "" {
+		return nil, nil
+	}
+	cmpFn, ok := peerSortFuncs[field]
+	if !ok {
+		return nil, fmt.Errorf("invalid --sort value %q; must be one of hostname, ip, lastseen, rx, tx", field)
+	}
+	return cmpFn, nil
+}
+
+// sortPeersBy sorts peers by the named field, as accepted by --sort. Peers
+// that compare equal keep the default order of [ipnstate.SortPeers]. An empty
+// field leaves just the default order.
+func sortPeersBy(peers []*ipnstate.PeerStatus, field string) error {
+	cmpFn, err := peerSortFunc(field)
+	if err != nil {
+		return err
+	}
+	ipnstate.SortPeers(peers)
+	if cmpFn != nil {
+		slices.SortStableFunc(peers, cmpFn)
+	}
+	return nil
 }
 
 func runStatus(ctx context.Context, args []string) error {
 	if len(args) > 0 {
 		return errors.New("unexpected non-flag arguments to 'tailscale status'")
 	}
+	if _, err := peerSortFunc(statusArgs.sort); err != nil {
+		return err
+	}
 	getStatus := localClient.Status
 	if !statusArgs.peers {
 		getStatus = localClient.StatusWithoutPeers
@@ -90,7 +153,18 @@ func runStatus(ctx context.Context, args []string) error {
 				}
 			}
 		}
-		j, err := json.MarshalIndent(st, "", "  ")
+		var v any = st
+		if statusArgs.sort != "" {
+			peers := make([]*ipnstate.PeerStatus, 0, len(st.Peer))
+			for _, ps := range st.Peer {
+				peers = append(peers, ps)
+			}
+			if err := sortPeersBy(peers, statusArgs.sort); err != nil {
+				return err
+			}
+			v = peers
+		}
+		j, err := json.MarshalIndent(v, "", "  ")
 		if err != nil {
 			return err
 		}
@@ -218,7 +292,9 @@ func runStatus(ctx context.Context, args []string) error {
 			}
 			peers = append(peers, ps)
 		}
-		ipnstate.SortPeers(peers)
+		if err := sortPeersBy(peers, statusArgs.sort); err != nil {
+			return err
+		}
 		for _, ps := range peers {
 			if statusArgs.active && !ps.Active {
 				continue
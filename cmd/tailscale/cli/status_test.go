@@ -0,0 +1,76 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cli
+
+import (
+	"net/netip"
+	"slices"
+	"testing"
+	"time"
+
+	"tailscale.com/ipn/ipnstate"
+)
+
+func TestSortPeersBy(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	peers := func() []*ipnstate.PeerStatus {
+		return []*ipnstate.PeerStatus{
+			{
+				DNSName:      "a.test.ts.net.",
+				HostName:     "charlie",
+				TailscaleIPs: []netip.Addr{netip.MustParseAddr("100.64.0.3")},
+				LastSeen:     now.Add(-time.Hour),
+				RxBytes:      10,
+				TxBytes:      300,
+			},
+			{
+				DNSName:      "b.test.ts.net.",
+				HostName:     "alpha",
+				TailscaleIPs: []netip.Addr{netip.MustParseAddr("100.64.0.10")},
+				LastSeen:     now,
+				RxBytes:      30,
+				TxBytes:      300,
+			},
+			{
+				DNSName:  "c.test.ts.net.",
+				HostName: "bravo",
+				LastSeen: now.Add(-time.Minute),
+				RxBytes:  20,
+				TxBytes:  100,
+			},
+		}
+	}
+
+	tests := []struct {
+		field string
+		want  []string // host names
+	}{
+		{"", []string{"charlie", "alpha", "bravo"}},
+		{"hostname", []string{"alpha", "bravo", "charlie"}},
+		{"ip", []string{"charlie", "alpha", "bravo"}},
+		{"lastseen", []string{"alpha", "bravo", "charlie"}},
+		{"rx", []string{"alpha", "bravo", "charlie"}},
+		{"tx", []string{"charlie", "alpha", "bravo"}}, // ties keep default order
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			ps := peers()
+			slices.Reverse(ps)
+			if err := sortPeersBy(ps, tt.field); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, p := range ps {
+				got = append(got, p.HostName)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := sortPeersBy(peers(), "os"); err == nil {
+		t.Error("sortPeersBy with unknown field succeeded")
+	}
+}
@@ -10,6 +10,7 @@
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/netip"
 	"net/url"
@@ -20,6 +21,7 @@
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -194,32 +196,100 @@ func (a upArgsT) getAuthKey() (string, error) {
 
 var upArgsGlobal upArgsT
 
-// Fields output when `tailscale up --json` is used. Two JSON blocks will be output.
+// Fields output when `tailscale up --json` is used. Each JSON object is
+// output on its own line: zero or more events while waiting for the backend,
+// followed by a final result.
 //
-// When "tailscale up" is run it first outputs a block with AuthURL and QR populated,
-// providing the link for where to authenticate this client. BackendState would be
-// valid but boring, as it will almost certainly be "NeedsLogin". Error would be
-// populated if something goes badly wrong.
+// If the client needs to be authenticated, an event with AuthURL and QR
+// populated is output, providing the link for where to authenticate this
+// client. BackendState would be valid but boring, as it will almost certainly
+// be "NeedsLogin". If the machine then needs approval by an admin, an event
+// with BackendState "NeedsMachineAuth" is output.
 //
-// When the client is authenticated by having someone visit the AuthURL, a second
-// JSON block will be output. The AuthURL and QR fields will not be present, the
-// BackendState and Error fields will give the result of the authentication.
+// Once "tailscale up" is done, a final result is output. It has LoggedIn and
+// BackendState set, AuthURL set to the URL that was used to log in (if any),
+// and any Warnings from health checks worth fixing. If "tailscale up" fails,
+// the final result instead has Error set to a description of the error, and
+// AuthURL set if one was output.
 // Ex:
 //
-//	{
-//	   "AuthURL": "https://login.tailscale.com/a/0123456789abcdef",
-//	   "QR": "data:image/png;base64,0123...cdef"
-//	   "BackendState": "NeedsLogin"
-//	}
-//
-//	{
-//	   "BackendState": "Running"
-//	}
+//	{"AuthURL":"https://login.tailscale.com/a/0123456789abcdef","QR":"data:image/png;base64,0123...cdef","BackendState":"NeedsLogin"}
+//	{"AuthURL":"https://login.tailscale.com/a/0123456789abcdef","LoggedIn":true,"BackendState":"Running"}
 type upOutputJSON struct {
-	AuthURL      string `json:",omitempty"` // Authentication URL of the form https://login.tailscale.com/a/0123456789
-	QR           string `json:",omitempty"` // a DataURL (base64) PNG of a QR code AuthURL
-	BackendState string `json:",omitempty"` // name of state like Running or NeedsMachineAuth
-	Error        string `json:",omitempty"` // description of an error
+	AuthURL      string   `json:",omitempty"` // Authentication URL of the form https://login.tailscale.com/a/0123456789
+	QR           string   `json:",omitempty"` // a DataURL (base64) PNG of a QR code AuthURL
+	LoggedIn     bool     `json:",omitempty"` // in the final result, whether the client is logged in
+	BackendState string   `json:",omitempty"` // name of state like Running or NeedsMachineAuth
+	Warnings     []string `json:",omitempty"` // in the final result, health warnings worth fixing
+	Error        string   `json:",omitempty"` // in a failed final result, description of the error
+}
+
+// upJSONWriter writes the output of "tailscale up --json"; see upOutputJSON.
+// It is safe for concurrent use.
+type upJSONWriter struct {
+	w io.Writer
+
+	mu      sync.Mutex
+	authURL string // last auth URL output, if any
+}
+
+// write writes js to w as a single line.
+func (o *upJSONWriter) write(js *upOutputJSON) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	data, err := json.Marshal(js)
+	if err != nil {
+		log.Printf("upOutputJSON marshalling error: %v", err)
+		return
+	}
+	data = append(data, '\n')
+	o.w.Write(data)
+}
+
+// authURLEvent outputs an event telling the user to visit authURL, while the
+// backend is in the named state.
+func (o *upJSONWriter) authURLEvent(authURL, state string) {
+	o.mu.Lock()
+	o.authURL = authURL
+	o.mu.Unlock()
+	js := &upOutputJSON{AuthURL: authURL, BackendState: state}
+	q, err := qrcode.New(authURL, qrcode.Medium)
+	if err == nil {
+		png, err := q.PNG(128)
+		if err == nil {
+			js.QR = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+		}
+	}
+	o.write(js)
+}
+
+// stateEvent outputs an event for a backend state change the user may need
+// to act on, such as NeedsMachineAuth.
+func (o *upJSONWriter) stateEvent(state ipn.State) {
+	o.write(&upOutputJSON{BackendState: state.String()})
+}
+
+// result outputs the final result, given the status once "tailscale up" is
+// done.
+func (o *upJSONWriter) result(st *ipnstate.Status) {
+	o.mu.Lock()
+	authURL := o.authURL
+	o.mu.Unlock()
+	state := st.BackendState
+	o.write(&upOutputJSON{
+		AuthURL:      authURL,
+		LoggedIn:     state != ipn.NeedsLogin.String() && state != ipn.NoState.String(),
+		BackendState: state,
+		Warnings:     upWarnings(st),
+	})
+}
+
+// errorResult outputs the final result of a failed "tailscale up".
+func (o *upJSONWriter) errorResult(err error) {
+	o.mu.Lock()
+	authURL := o.authURL
+	o.mu.Unlock()
+	o.write(&upOutputJSON{AuthURL: authURL, Error: err.Error()})
 }
 
 func warnf(format string, args ...any) {
@@ -418,6 +488,25 @@ func runUp(ctx context.Context, cmd string, args []string, upArgs upArgsT) (retE
 		}
 	}
 
+	jsonOut := &upJSONWriter{w: Stdout}
+	defer func() {
+		if !upArgs.json {
+			if retErr == nil {
+				checkUpWarnings(ctx)
+			}
+			return
+		}
+		if retErr == nil {
+			st, err := localClient.StatusWithoutPeers(ctx)
+			if err == nil {
+				jsonOut.result(st)
+				return
+			}
+			retErr = fixTailscaledConnectError(err)
+		}
+		jsonOut.errorResult(retErr)
+	}()
+
 	st, err := localClient.Status(ctx)
 	if err != nil {
 		return fixTailscaledConnectError(err)
@@ -485,12 +574,6 @@ func runUp(ctx context.Context, cmd string, args []string, upArgs upArgsT) (retE
 		curExitNodeIP: exitNodeIP(curPrefs, st),
 	}
 
-	defer func() {
-		if retErr == nil {
-			checkUpWarnings(ctx)
-		}
-	}()
-
 	simpleUp, justEditMP, err := updatePrefs(prefs, curPrefs, env)
 	if err != nil {
 		fatalf("%s", err)
@@ -582,15 +665,14 @@ func runUp(ctx context.Context, cmd string, args []string, upArgs upArgsT) (retE
 				case ipn.NeedsMachineAuth:
 					printed = true
 					if env.upArgs.json {
-						printUpDoneJSON(ipn.NeedsMachineAuth, "")
+						jsonOut.stateEvent(ipn.NeedsMachineAuth)
 					} else {
 						fmt.Fprintf(Stderr, "\nTo approve your machine, visit (as admin):\n\n\t%s\n\n", prefs.AdminPageURL())
 					}
 				case ipn.Running:
-					// Done full authentication process
-					if env.upArgs.json {
-						printUpDoneJSON(ipn.Running, "")
-					} else if printed {
+					// Done full authentication process. In JSON mode,
+					// the final result is output once runUp returns.
+					if !env.upArgs.json && printed {
 						// Only need to print an update if we printed the "please click" message earlier.
 						fmt.Fprintf(Stderr, "Success.\n")
 					}
@@ -609,22 +691,7 @@ func runUp(ctx context.Context, cmd string, args []string, upArgs upArgsT) (retE
 				printed = true
 				lastURLPrinted = authURL
 				if upArgs.json {
-					js := &upOutputJSON{AuthURL: authURL, BackendState: st.BackendState}
-
-					q, err := qrcode.New(authURL, qrcode.Medium)
-					if err == nil {
-						png, err := q.PNG(128)
-						if err == nil {
-							js.QR = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
-						}
-					}
-
-					data, err := json.MarshalIndent(js, "", "\t")
-					if err != nil {
-						printf("upOutputJSON marshalling error: %v", err)
-					} else {
-						outln(string(data))
-					}
+					jsonOut.authURLEvent(authURL, st.BackendState)
 				} else {
 					fmt.Fprintf(Stderr, "\nTo authenticate, visit:\n\n\t%s\n\n", authURL)
 					if upArgs.qr {
@@ -695,12 +762,7 @@ func checkUpWarnings(ctx context.Context) {
 		// Ignore. Don't spam more.
 		return
 	}
-	var warn []string
-	for _, w := range st.Health {
-		if upWorthyWarning(w) {
-			warn = append(warn, w)
-		}
-	}
+	warn := upWarnings(st)
 	if len(warn) == 0 {
 		return
 	}
@@ -714,14 +776,16 @@ func checkUpWarnings(ctx context.Context) {
 	}
 }
 
-func printUpDoneJSON(state ipn.State, errorString string) {
-	js := &upOutputJSON{BackendState: state.String(), Error: errorString}
-	data, err := json.MarshalIndent(js, "", "  ")
-	if err != nil {
-		log.Printf("printUpDoneJSON marshalling error: %v", err)
-	} else {
-		outln(string(data))
+// upWarnings returns the health warnings in st worth reporting at the end of
+// "tailscale up".
+func upWarnings(st *ipnstate.Status) []string {
+	var warn []string
+	for _, w := range st.Health {
+		if upWorthyWarning(w) {
+			warn = append(warn, w)
+		}
 	}
+	return warn
 }
 
 var (
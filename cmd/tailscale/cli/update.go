@@ -8,6 +8,8 @@
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"os"
 	"runtime"
 	"strings"
 
@@ -91,9 +93,15 @@ func confirmUpdate(ver string) bool {
 // PromptYesNo takes a question and prompts the user to answer the
 // question with a yes or no. It appends a [y/n] to the message.
 func promptYesNo(msg string) bool {
-	fmt.Print(msg + " [y/n] ")
+	return promptYesNoFrom(os.Stdin, os.Stdout, msg)
+}
+
+// promptYesNoFrom is like promptYesNo, but writes the question to w and
+// reads the answer from r.
+func promptYesNoFrom(r io.Reader, w io.Writer, msg string) bool {
+	fmt.Fprint(w, msg+" [y/n] ")
 	var resp string
-	fmt.Scanln(&resp)
+	fmt.Fscanln(r, &resp)
 	resp = strings.ToLower(resp)
 	switch resp {
 	case "y", "yes", "sure":
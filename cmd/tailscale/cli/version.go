@@ -8,6 +8,7 @@
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"tailscale.com/clientupdate"
@@ -24,15 +25,24 @@
 		fs.BoolVar(&versionArgs.daemon, "daemon", false, "also print local node's daemon version")
 		fs.BoolVar(&versionArgs.json, "json", false, "output in JSON format")
 		fs.BoolVar(&versionArgs.upstream, "upstream", false, "fetch and print the latest upstream release version from pkgs.tailscale.com")
+		fs.BoolVar(&versionArgs.checkDaemon, "check-daemon", false, "print the client and daemon versions and exit with an error if they differ")
 		return fs
 	})(),
 	Exec: runVersion,
 }
 
 var versionArgs struct {
-	daemon   bool // also check local node's daemon version
-	json     bool
-	upstream bool
+	daemon      bool // also check local node's daemon version
+	json        bool
+	upstream    bool
+	checkDaemon bool // compare client and daemon versions
+}
+
+// versionCheck is the JSON output of "tailscale version --check-daemon".
+type versionCheck struct {
+	Client string `json:"client"`
+	Daemon string `json:"daemon"`
+	Match  bool   `json:"match"`
 }
 
 func runVersion(ctx context.Context, args []string) error {
@@ -42,6 +52,14 @@ func runVersion(ctx context.Context, args []string) error {
 	var err error
 	var st *ipnstate.Status
 
+	if versionArgs.checkDaemon {
+		st, err = localClient.StatusWithoutPeers(ctx)
+		if err != nil {
+			return fixTailscaledConnectError(err)
+		}
+		return checkDaemonVersion(Stdout, version.Long(), st.Version, versionArgs.json)
+	}
+
 	if versionArgs.daemon {
 		st, err = localClient.StatusWithoutPeers(ctx)
 		if err != nil {
@@ -88,3 +106,30 @@ func runVersion(ctx context.Context, args []string) error {
 	}
 	return nil
 }
+
+// checkDaemonVersion writes the client and daemon versions to w, along with
+// whether they match, and returns an error if they don't.
+func checkDaemonVersion(w io.Writer, clientVer, daemonVer string, asJSON bool) error {
+	vc := versionCheck{
+		Client: clientVer,
+		Daemon: daemonVer,
+		Match:  clientVer == daemonVer,
+	}
+	if asJSON {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "\t")
+		if err := e.Encode(vc); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(w, "Client: %s\n", vc.Client)
+		fmt.Fprintf(w, "Daemon: %s\n", vc.Daemon)
+		if vc.Match {
+			fmt.Fprintf(w, "Versions match.\n")
+		}
+	}
+	if !vc.Match {
+		return fmt.Errorf("client version %q does not match daemon version %q", clientVer, daemonVer)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCheckDaemonVersion(t *testing.T) {
+	const (
+		v1 = "1.70.0-t0123456789-g0123456789"
+		v2 = "1.72.1-tabcdef0123-gabcdef0123"
+	)
+	tests := []struct {
+		name      string
+		daemon    string
+		wantMatch bool
+	}{
+		{"match", v1, true},
+		{"mismatch", v2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := checkDaemonVersion(&buf, v1, tt.daemon, false)
+			if (err == nil) != tt.wantMatch {
+				t.Errorf("got error %v; want match %v", err, tt.wantMatch)
+			}
+			out := buf.String()
+			if !strings.Contains(out, "Client: "+v1) || !strings.Contains(out, "Daemon: "+tt.daemon) {
+				t.Errorf("output does not show both versions:\n%s", out)
+			}
+			if got := strings.Contains(out, "Versions match."); got != tt.wantMatch {
+				t.Errorf("output reports match = %v; want %v:\n%s", got, tt.wantMatch, out)
+			}
+
+			buf.Reset()
+			err = checkDaemonVersion(&buf, v1, tt.daemon, true)
+			if (err == nil) != tt.wantMatch {
+				t.Errorf("JSON: got error %v; want match %v", err, tt.wantMatch)
+			}
+			var got versionCheck
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("decoding %q: %v", buf.Bytes(), err)
+			}
+			want := versionCheck{Client: v1, Daemon: tt.daemon, Match: tt.wantMatch}
+			if got != want {
+				t.Errorf("JSON = %+v; want %+v", got, want)
+			}
+		})
+	}
+}
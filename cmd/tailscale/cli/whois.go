@@ -9,29 +9,39 @@
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"slices"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
+	"tailscale.com/client/tailscale/apitype"
+	"tailscale.com/tailcfg"
 )
 
 var whoisCmd = &ffcli.Command{
 	Name:       "whois",
-	ShortUsage: "tailscale whois [--json] ip[:port]",
+	ShortUsage: "tailscale whois [--json] [--verbose] ip[:port]",
 	ShortHelp:  "Show the machine and user associated with a Tailscale IP (v4 or v6)",
 	LongHelp: strings.TrimSpace(`
 	'tailscale whois' shows the machine and user associated with a Tailscale IP (v4 or v6).
+
+	It also shows the capabilities granted to the machine by the tailnet
+	policy. With --verbose, it additionally shows the machine's node
+	attributes, which helps debug why a connection was allowed or denied.
 	`),
 	Exec: runWhoIs,
 	FlagSet: func() *flag.FlagSet {
 		fs := newFlagSet("whois")
-		fs.BoolVar(&whoIsArgs.json, "json", false, "output in JSON format")
+		fs.BoolVar(&whoIsArgs.json, "json", false, "output in JSON format; always includes all fields")
+		fs.BoolVar(&whoIsArgs.verbose, "verbose", false, "also include node attributes")
 		return fs
 	}(),
 }
 
 var whoIsArgs struct {
-	json bool // output in JSON format
+	json    bool // output in JSON format
+	verbose bool // also include node attributes
 }
 
 func runWhoIs(ctx context.Context, args []string) error {
@@ -50,8 +60,15 @@ func runWhoIs(ctx context.Context, args []string) error {
 		ec.Encode(who)
 		return nil
 	}
+	printWhoIs(Stdout, who, whoIsArgs.verbose)
+	return nil
+}
 
-	w := tabwriter.NewWriter(Stdout, 10, 5, 5, ' ', 0)
+// printWhoIs writes a human-readable form of who to w, including the
+// capabilities granted to the node. If verbose is set, the node's
+// attributes are included as well.
+func printWhoIs(out io.Writer, who *apitype.WhoIsResponse, verbose bool) {
+	w := tabwriter.NewWriter(out, 10, 5, 5, ' ', 0)
 	fmt.Fprintf(w, "Machine:\n")
 	fmt.Fprintf(w, "  Name:\t%s\n", strings.TrimSuffix(who.Node.Name, "."))
 	fmt.Fprintf(w, "  ID:\t%s\n", who.Node.StableID)
@@ -69,20 +86,39 @@ func runWhoIs(ctx context.Context, args []string) error {
 	w.Flush()
 	w = nil // avoid accidental use
 
+	if cm := who.Node.CapMap; verbose && len(cm) > 0 {
+		fmt.Fprintf(out, "Node attributes:\n")
+		for _, cap := range sortedCapKeys(cm) {
+			printCapValues(out, string(cap), cm[cap])
+		}
+	}
 	if cm := who.CapMap; len(cm) > 0 {
-		printf("Capabilities:\n")
-		for cap, vals := range cm {
-			// To make the output more readable, we have to reindent the JSON
-			// values so they line up with the cap name.
-			if len(vals) > 0 {
-				v, _ := json.MarshalIndent(vals, "      ", "  ")
-
-				printf("  - %s:\n", cap)
-				printf("      %s\n", v)
-			} else {
-				printf("  - %s\n", cap)
-			}
+		fmt.Fprintf(out, "Capabilities:\n")
+		for _, cap := range sortedCapKeys(cm) {
+			printCapValues(out, string(cap), cm[cap])
 		}
 	}
-	return nil
+}
+
+// printCapValues writes a capability name and its optional values to w.
+func printCapValues(w io.Writer, cap string, vals []tailcfg.RawMessage) {
+	if len(vals) == 0 {
+		fmt.Fprintf(w, "  - %s\n", cap)
+		return
+	}
+	// To make the output more readable, we have to reindent the JSON
+	// values so they line up with the cap name.
+	v, _ := json.MarshalIndent(vals, "      ", "  ")
+	fmt.Fprintf(w, "  - %s:\n", cap)
+	fmt.Fprintf(w, "      %s\n", v)
+}
+
+// sortedCapKeys returns the keys of a capability map in sorted order.
+func sortedCapKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
 }
@@ -0,0 +1,71 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"tailscale.com/client/tailscale/apitype"
+	"tailscale.com/tailcfg"
+)
+
+func TestPrintWhoIs(t *testing.T) {
+	who := &apitype.WhoIsResponse{
+		Node: &tailcfg.Node{
+			Name:     "foo.test.ts.net.",
+			StableID: "nFoo",
+			Tags:     []string{"tag:server"},
+			CapMap: tailcfg.NodeCapMap{
+				tailcfg.NodeAttrFunnel: nil,
+			},
+		},
+		CapMap: tailcfg.PeerCapMap{
+			"example.com/cap/ssh":   nil,
+			"example.com/cap/admin": {`{"role":"owner"}`},
+		},
+	}
+
+	tests := []struct {
+		verbose  bool
+		want     []string
+		dontWant []string
+	}{
+		{
+			verbose: false,
+			want: []string{
+				"foo.test.ts.net", "nFoo", "tag:server",
+				"Capabilities:\n  - example.com/cap/admin:\n",
+				"  - example.com/cap/ssh\n",
+			},
+			dontWant: []string{"Node attributes:", "funnel"},
+		},
+		{
+			verbose: true,
+			want: []string{
+				"foo.test.ts.net", "tag:server",
+				"Node attributes:\n  - funnel\n",
+				"Capabilities:\n  - example.com/cap/admin:\n",
+				`"role": "owner"`,
+				"  - example.com/cap/ssh\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		printWhoIs(&buf, who, tt.verbose)
+		got := buf.String()
+		for _, s := range tt.want {
+			if !strings.Contains(got, s) {
+				t.Errorf("verbose=%v: output missing %q:\n%s", tt.verbose, s, got)
+			}
+		}
+		for _, s := range tt.dontWant {
+			if strings.Contains(got, s) {
+				t.Errorf("verbose=%v: output unexpectedly contains %q:\n%s", tt.verbose, s, got)
+			}
+		}
+	}
+}
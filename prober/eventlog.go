@@ -0,0 +1,75 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"sync"
+	"time"
+)
+
+// ProbeEvent is a record of a single probe run, as retained by the event
+// log enabled with WithEventLog.
+type ProbeEvent struct {
+	Time    time.Time     // when the run finished
+	Probe   string        // probe name
+	Class   string        // probe class
+	Result  bool          // whether the run succeeded
+	Latency time.Duration // how long the run took, successful or not
+	Error   string        `json:",omitempty"`
+}
+
+// eventLog is a fixed-size ring of the most recent ProbeEvents. A nil
+// *eventLog is valid and records nothing.
+type eventLog struct {
+	mu     sync.Mutex
+	events []ProbeEvent // ring buffer; len is the configured size
+	next   int          // index in events to write to next
+	full   bool         // whether events has wrapped around at least once
+}
+
+func newEventLog(size int) *eventLog {
+	return &eventLog{events: make([]ProbeEvent, size)}
+}
+
+// add records ev, evicting the oldest event if the log is full.
+func (l *eventLog) add(ev ProbeEvent) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events[l.next] = ev
+	l.next++
+	if l.next == len(l.events) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// recent returns up to n of the most recent events, oldest first. If n is
+// zero or negative, all retained events are returned.
+func (l *eventLog) recent(n int) []ProbeEvent {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	count := l.next
+	if l.full {
+		count = len(l.events)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+	out := make([]ProbeEvent, 0, n)
+	for i := count - n; i < count; i++ {
+		// Index relative to the oldest retained event.
+		j := i
+		if l.full {
+			j = (l.next + i) % len(l.events)
+		}
+		out = append(out, l.events[j])
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestEventLog(t *testing.T) {
+	l := newEventLog(3)
+	names := func(evs []ProbeEvent) []string {
+		var out []string
+		for _, ev := range evs {
+			out = append(out, ev.Probe)
+		}
+		return out
+	}
+
+	if got := l.recent(0); len(got) != 0 {
+		t.Errorf("empty log: got %v", names(got))
+	}
+	for i := range 2 {
+		l.add(ProbeEvent{Probe: fmt.Sprint(i)})
+	}
+	if got, want := names(l.recent(0)), []string{"0", "1"}; !slices.Equal(got, want) {
+		t.Errorf("before wrapping: got %v; want %v", got, want)
+	}
+	for i := 2; i < 7; i++ {
+		l.add(ProbeEvent{Probe: fmt.Sprint(i)})
+	}
+	if got, want := names(l.recent(0)), []string{"4", "5", "6"}; !slices.Equal(got, want) {
+		t.Errorf("after wrapping: got %v; want %v", got, want)
+	}
+	if got, want := names(l.recent(2)), []string{"5", "6"}; !slices.Equal(got, want) {
+		t.Errorf("recent(2): got %v; want %v", got, want)
+	}
+	if got, want := names(l.recent(10)), []string{"4", "5", "6"}; !slices.Equal(got, want) {
+		t.Errorf("recent(10): got %v; want %v", got, want)
+	}
+
+	var nilLog *eventLog
+	nilLog.add(ProbeEvent{})
+	if got := nilLog.recent(1); got != nil {
+		t.Errorf("nil log: got %v", got)
+	}
+}
+
+func TestRecentEvents(t *testing.T) {
+	clk := newFakeTime()
+	p := newForTest(clk.Now, clk.NewTicker).WithOnce(true).WithEventLog(2)
+
+	p.Run("ok", probeInterval, nil, FuncProbe(func(context.Context) error { return nil }))
+	p.Wait()
+	p.Run("fail", probeInterval, nil, FuncProbe(func(context.Context) error { return errors.New("boom") }))
+	p.Wait()
+
+	got := p.RecentEvents(0)
+	want := []ProbeEvent{
+		{Time: epoch, Probe: "ok", Result: true},
+		{Time: epoch, Probe: "fail", Result: false, Error: "boom"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %+v; want %+v", got, want)
+	}
+
+	p.Run("third", probeInterval, nil, FuncProbe(func(context.Context) error { return nil }))
+	p.Wait()
+	got = p.RecentEvents(0)
+	if len(got) != 2 || got[0].Probe != "fail" || got[1].Probe != "third" {
+		t.Errorf("after eviction: got %+v; want [fail third]", got)
+	}
+
+	if got := newForTest(clk.Now, clk.NewTicker).RecentEvents(10); got != nil {
+		t.Errorf("disabled event log: got %+v; want nil", got)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+// ExitPolicy decides whether a set of probe results counts as a failure,
+// given the number of failed probes and the total number of probes.
+// See Prober.ExitCode.
+type ExitPolicy func(failed, total int) bool
+
+var (
+	// AnyFail is an ExitPolicy that fails if any probe failed.
+	AnyFail ExitPolicy = func(failed, total int) bool { return failed > 0 }
+
+	// AllFail is an ExitPolicy that fails if there is at least one probe
+	// and all probes failed.
+	AllFail ExitPolicy = func(failed, total int) bool { return total > 0 && failed == total }
+)
+
+// ThresholdFail returns an ExitPolicy that fails if at least n probes
+// failed.
+func ThresholdFail(n int) ExitPolicy {
+	return func(failed, total int) bool { return failed >= n }
+}
+
+// ExitCode returns a process exit code for the latest results of all
+// probes according to policy: 1 if policy reports a failure, or 0
+// otherwise. A probe that has not completed a run yet counts as failed.
+//
+// It's intended for use with WithOnce, after Wait returns, so that the
+// prober can be used as a health gate in scripts.
+func (p *Prober) ExitCode(policy ExitPolicy) int {
+	p.mu.Lock()
+	probes := make([]*Probe, 0, len(p.probes))
+	for _, probe := range p.probes {
+		probes = append(probes, probe)
+	}
+	p.mu.Unlock()
+
+	var failed int
+	for _, probe := range probes {
+		probe.mu.Lock()
+		if probe.end.IsZero() || !probe.succeeded {
+			failed++
+		}
+		probe.mu.Unlock()
+	}
+	if policy(failed, len(probes)) {
+		return 1
+	}
+	return 0
+}
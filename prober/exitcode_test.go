@@ -0,0 +1,48 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		results  []bool // whether each probe succeeds
+		policy   ExitPolicy
+		wantCode int
+	}{
+		{"any/all-pass", []bool{true, true, true}, AnyFail, 0},
+		{"any/one-fail", []bool{true, false, true}, AnyFail, 1},
+		{"any/no-probes", nil, AnyFail, 0},
+		{"all/one-fail", []bool{true, false, true}, AllFail, 0},
+		{"all/all-fail", []bool{false, false}, AllFail, 1},
+		{"all/no-probes", nil, AllFail, 0},
+		{"threshold/below", []bool{false, true, true}, ThresholdFail(2), 0},
+		{"threshold/at", []bool{false, false, true}, ThresholdFail(2), 1},
+		{"threshold/above", []bool{false, false, false}, ThresholdFail(2), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := newFakeTime()
+			p := newForTest(clk.Now, clk.NewTicker).WithOnce(true)
+			for i, ok := range tt.results {
+				p.Run(fmt.Sprintf("probe%d", i), probeInterval, nil, FuncProbe(func(context.Context) error {
+					if ok {
+						return nil
+					}
+					return errors.New("failing, as instructed by test")
+				}))
+			}
+			p.Wait()
+			if got := p.ExitCode(tt.policy); got != tt.wantCode {
+				t.Errorf("ExitCode = %d; want %d", got, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"tailscale.com/syncs"
+	"tailscale.com/util/multierr"
+)
+
+// AndOr specifies how the results of the individual targets of a multi-target
+// probe are combined into the probe's overall result.
+type AndOr int
+
+const (
+	// And requires all targets to succeed.
+	And AndOr = iota
+	// Or requires at least one target to succeed.
+	Or
+)
+
+// defaultHTTPPoolConcurrency is the default maximum number of targets of an
+// HTTPPool probe that are fetched at the same time.
+const defaultHTTPPoolConcurrency = 8
+
+// HTTPPoolOpt is an option for HTTPPool.
+type HTTPPoolOpt func(*httpPool)
+
+// WithHTTPPoolConcurrency sets the maximum number of targets of an HTTPPool
+// probe that are fetched concurrently. Values less than 1 are treated as 1.
+func WithHTTPPoolConcurrency(n int) HTTPPoolOpt {
+	return func(hp *httpPool) {
+		hp.concurrency = max(n, 1)
+	}
+}
+
+// WithHTTPPoolWantText sets text that must be present in the response body of
+// every target of an HTTPPool probe.
+func WithHTTPPoolWantText(s string) HTTPPoolOpt {
+	return func(hp *httpPool) {
+		hp.want = []byte(s)
+	}
+}
+
+// httpPool is the state of an HTTPPool probe.
+type httpPool struct {
+	urls        []string
+	mode        AndOr
+	concurrency int
+	want        []byte
+
+	mu      sync.Mutex
+	results map[string]httpPoolResult // by URL; results of the latest run
+}
+
+type httpPoolResult struct {
+	ok      bool
+	latency time.Duration
+}
+
+// HTTPPool returns a ProbeClass that healthchecks a pool of HTTP URLs, such as
+// a set of replicas of a service, in a single probe.
+//
+// Each run fetches every URL the same way as HTTP, with at most a bounded
+// number of requests in flight. The probe succeeds if all URLs (for And) or
+// any URL (for Or) succeed. Per-URL results are exported as metrics with a
+// "target" label.
+func HTTPPool(urls []string, mode AndOr, opts ...HTTPPoolOpt) ProbeClass {
+	hp := &httpPool{
+		urls:        urls,
+		mode:        mode,
+		concurrency: defaultHTTPPoolConcurrency,
+		results:     map[string]httpPoolResult{},
+	}
+	for _, o := range opts {
+		o(hp)
+	}
+	return ProbeClass{
+		Probe:   hp.probe,
+		Class:   "http_pool",
+		Metrics: hp.metrics,
+	}
+}
+
+func (hp *httpPool) probe(ctx context.Context) error {
+	if len(hp.urls) == 0 {
+		return errors.New("no targets")
+	}
+	sem := syncs.NewSemaphore(hp.concurrency)
+	errs := make([]error, len(hp.urls))
+	results := make(map[string]httpPoolResult, len(hp.urls))
+	var mu sync.Mutex // protects results
+	var wg sync.WaitGroup
+	for i, url := range hp.urls {
+		if !sem.AcquireContext(ctx) {
+			errs[i] = fmt.Errorf("fetching %q: %w", url, ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer sem.Release()
+			start := time.Now()
+			errs[i] = probeHTTP(ctx, url, hp.want)
+			r := httpPoolResult{ok: errs[i] == nil}
+			if r.ok {
+				r.latency = time.Since(start)
+			}
+			mu.Lock()
+			results[url] = r
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	hp.mu.Lock()
+	hp.results = results
+	hp.mu.Unlock()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	switch {
+	case len(failed) == 0:
+		return nil
+	case hp.mode == Or && len(failed) < len(hp.urls):
+		return nil
+	}
+	return fmt.Errorf("%d of %d targets failed: %w", len(failed), len(hp.urls), multierr.New(failed...))
+}
+
+func (hp *httpPool) metrics(l prometheus.Labels) []prometheus.Metric {
+	hp.mu.Lock()
+	defer hp.mu.Unlock()
+	mResult := prometheus.NewDesc("http_pool_target_result", "Latest result of a pool target (1 = success, 0 = failure)", []string{"target"}, l)
+	mLatency := prometheus.NewDesc("http_pool_target_latency_millis", "Latest latency of a successful pool target (ms)", []string{"target"}, l)
+	mHealthy := prometheus.NewDesc("http_pool_healthy_targets", "Number of pool targets that succeeded in the latest run", nil, l)
+
+	var ms []prometheus.Metric
+	var healthy int
+	for _, url := range hp.urls {
+		r, ok := hp.results[url]
+		if !ok {
+			continue
+		}
+		var v float64
+		if r.ok {
+			v = 1
+			healthy++
+			ms = append(ms, prometheus.MustNewConstMetric(mLatency, prometheus.GaugeValue, float64(r.latency.Milliseconds()), url))
+		}
+		ms = append(ms, prometheus.MustNewConstMetric(mResult, prometheus.GaugeValue, v, url))
+	}
+	ms = append(ms, prometheus.MustNewConstMetric(mHealthy, prometheus.GaugeValue, float64(healthy)))
+	return ms
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPPool(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "broken", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	tests := []struct {
+		name    string
+		urls    []string
+		mode    AndOr
+		wantErr bool
+	}{
+		{"and-all-healthy", []string{healthy.URL, healthy.URL + "/2"}, And, false},
+		{"and-one-failing", []string{healthy.URL, failing.URL}, And, true},
+		{"or-one-failing", []string{healthy.URL, failing.URL}, Or, false},
+		{"or-all-failing", []string{failing.URL, failing.URL + "/2"}, Or, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := newFakeTime()
+			p := newForTest(clk.Now, clk.NewTicker).WithOnce(true)
+			p.Run(tt.name, probeInterval, nil, HTTPPool(tt.urls, tt.mode, WithHTTPPoolWantText("ok")))
+			p.Wait()
+			if got := p.ProbeInfo()[tt.name]; got.Result == tt.wantErr {
+				t.Fatalf("got result %v (error %q); want error: %v", got.Result, got.Error, tt.wantErr)
+			}
+
+			mfs, err := p.metrics.Gather()
+			if err != nil {
+				t.Fatal(err)
+			}
+			results := map[string]float64{}
+			var healthyTargets float64
+			for _, mf := range mfs {
+				for _, m := range mf.GetMetric() {
+					switch mf.GetName() {
+					case "prober_http_pool_healthy_targets":
+						healthyTargets = m.GetGauge().GetValue()
+					case "prober_http_pool_target_result":
+						for _, lp := range m.GetLabel() {
+							if lp.GetName() == "target" {
+								results[lp.GetValue()] = m.GetGauge().GetValue()
+							}
+						}
+					}
+				}
+			}
+			var wantHealthy float64
+			for _, u := range tt.urls {
+				want := 0.0
+				if u == healthy.URL || u == healthy.URL+"/2" {
+					want = 1
+					wantHealthy++
+				}
+				if got, ok := results[u]; !ok || got != want {
+					t.Errorf("target %s: result = %v (present: %v); want %v", u, got, ok, want)
+				}
+			}
+			if healthyTargets != wantHealthy {
+				t.Errorf("healthy targets = %v; want %v", healthyTargets, wantHealthy)
+			}
+		})
+	}
+}
+
+func TestHTTPPoolConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	var urls []string
+	for range 10 {
+		urls = append(urls, srv.URL)
+	}
+	pc := HTTPPool(urls, And, WithHTTPPoolConcurrency(2))
+	if err := pc.Probe(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("max in-flight requests = %d; want <= 2", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := pc.Probe(ctx); err == nil {
+		t.Error("canceled context: got nil error")
+	}
+}
@@ -14,10 +14,15 @@
 	"log"
 	"maps"
 	"math/rand"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"tailscale.com/types/opt"
+	"tailscale.com/types/ptr"
+	"tailscale.com/version"
 )
 
 // ProbeClass defines a probe of a specific type: a probing function that will
@@ -57,12 +62,34 @@ type Prober struct {
 	// Whether to run all probes once instead of running them in a loop.
 	once bool
 
+	// SLO target success ratio and rolling window applied to all probes.
+	// SLO tracking is disabled if sloWindow is zero.
+	sloTarget float64
+	sloWindow time.Duration
+
+	// events retains recent probe runs if enabled with WithEventLog.
+	// It is nil otherwise.
+	events *eventLog
+
+	// notify, if non-nil, is called when a probe's verdict changes.
+	// See WithNotifier.
+	notify func(Transition)
+
+	// tracer, if non-nil, starts a span for each probe run.
+	// See WithTracer.
+	tracer Tracer
+
+	// limiter, if non-nil, is waited on before each probe run.
+	// See WithRateLimiter.
+	limiter *rate.Limiter
+
 	// Time-related functions that get faked out during tests.
 	now       func() time.Time
 	newTicker func(time.Duration) ticker
 
 	mu     sync.Mutex // protects all following fields
 	probes map[string]*Probe
+	self   *selfProbe // nil unless enabled with WithSelfProbe
 
 	namespace string
 	metrics   *prometheus.Registry
@@ -74,6 +101,17 @@ func New() *Prober {
 }
 
 func newForTest(now func() time.Time, newTicker func(time.Duration) ticker) *Prober {
+	p := newUnregistered(now, newTicker)
+	prometheus.DefaultRegisterer.MustRegister(p.metrics)
+	addMetaProber(p)
+	return p
+}
+
+// newUnregistered returns a new Prober whose metrics are kept in its own
+// registry, without being exported by the default registerer or included
+// in the meta metrics. It is used for short-lived Probers, such as the one
+// created by RunProbesOnce, which must not touch process-wide state.
+func newUnregistered(now func() time.Time, newTicker func(time.Duration) ticker) *Prober {
 	p := &Prober{
 		now:       now,
 		newTicker: newTicker,
@@ -81,19 +119,39 @@ func newForTest(now func() time.Time, newTicker func(time.Duration) ticker) *Pro
 		metrics:   prometheus.NewRegistry(),
 		namespace: "prober",
 	}
-	prometheus.DefaultRegisterer.MustRegister(p.metrics)
+	p.metrics.MustRegister(selfCollector{p})
 	return p
 }
 
+// unregisterMetrics removes p's metrics from the default registerer and the
+// meta metrics. It is used in tests to clean up after Probers created with
+// New or newForTest.
+func (p *Prober) unregisterMetrics() {
+	prometheus.DefaultRegisterer.Unregister(p.metrics)
+	removeMetaProber(p)
+}
+
 // Run executes probe class function every interval, and exports probe results under probeName.
 //
-// Registering a probe under an already-registered name panics.
+// Registering a probe under an already-registered name panics; use TryRun
+// to get an error instead.
 func (p *Prober) Run(name string, interval time.Duration, labels Labels, pc ProbeClass) *Probe {
+	probe, err := p.TryRun(name, interval, labels, pc)
+	if err != nil {
+		panic(err)
+	}
+	return probe
+}
+
+// TryRun is like Run, but returns an error rather than panicking if a
+// probe is already registered under name, or if its metrics conflict with
+// an already-registered probe. Existing probes are never reused, as there's
+// no way to tell whether their ProbeClass matches pc: when probes are
+// re-created from configuration that may have changed, Close the existing
+// probe before registering its replacement.
+func (p *Prober) TryRun(name string, interval time.Duration, labels Labels, pc ProbeClass) (*Probe, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	if _, ok := p.probes[name]; ok {
-		panic(fmt.Sprintf("probe named %q already registered", name))
-	}
 
 	l := prometheus.Labels{
 		"name":  name,
@@ -106,6 +164,16 @@ func (p *Prober) Run(name string, interval time.Duration, labels Labels, pc Prob
 		l[k] = v
 	}
 
+	if existing, ok := p.probes[name]; ok {
+		if !maps.Equal(existing.metricLabels, l) {
+			return nil, fmt.Errorf("probe named %q already registered with labels %v; cannot re-register with labels %v", name, existing.metricLabels, l)
+		}
+		if existing.interval != interval {
+			return nil, fmt.Errorf("probe named %q already registered with interval %v; cannot re-register with interval %v", name, existing.interval, interval)
+		}
+		return nil, fmt.Errorf("probe named %q already registered; Close it before registering it again", name)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	probe := &Probe{
 		prober:  p,
@@ -132,19 +200,33 @@ func (p *Prober) Run(name string, interval time.Duration, labels Labels, pc Prob
 		}, []string{"status"}),
 	}
 
-	prometheus.WrapRegistererWithPrefix(p.namespace+"_", p.metrics).MustRegister(probe.metrics)
+	if p.sloWindow > 0 {
+		probe.slo = &sloTracker{target: p.sloTarget, window: p.sloWindow}
+		probe.mSLOBudget = prometheus.NewDesc("slo_budget_remaining", "Fraction of the SLO error budget remaining over the rolling window", nil, l)
+		probe.mSLOViolation = prometheus.NewDesc("slo_violation", "Whether the success ratio over the rolling window is below the SLO target (1 = violated, 0 = met)", nil, l)
+	}
+
 	probe.metrics.MustRegister(probe)
+	if err := prometheus.WrapRegistererWithPrefix(p.namespace+"_", p.metrics).Register(probe.metrics); err != nil {
+		cancel()
+		if errors.As(err, new(prometheus.AlreadyRegisteredError)) {
+			return nil, fmt.Errorf("probe %q: metrics conflict with an already-registered probe: %w", name, err)
+		}
+		return nil, fmt.Errorf("probe %q: registering metrics: %w", name, err)
+	}
 
 	p.probes[name] = probe
 	go probe.loop()
-	return probe
+	return probe, nil
 }
 
 func (p *Prober) unregister(probe *Probe) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	// Unregister probe.metrics the same way it was registered, and before
+	// it is emptied, so that its collector ID matches.
+	prometheus.WrapRegistererWithPrefix(p.namespace+"_", p.metrics).Unregister(probe.metrics)
 	probe.metrics.Unregister(probe)
-	p.metrics.Unregister(probe.metrics)
 	name := probe.name
 	delete(p.probes, name)
 }
@@ -163,8 +245,40 @@ func (p *Prober) WithOnce(s bool) *Prober {
 	return p
 }
 
+// WithSLO enables tracking of each probe's success ratio over a rolling
+// window of the given duration against targetRatio (e.g. 0.999). The
+// remaining error budget and whether the SLO is currently violated are
+// exported as metrics. It only affects probes added after it is called.
+func (p *Prober) WithSLO(targetRatio float64, window time.Duration) *Prober {
+	p.sloTarget = targetRatio
+	p.sloWindow = window
+	return p
+}
+
+// WithEventLog enables an in-memory log of the most recent size probe runs
+// across all probes, which can be retrieved with RecentEvents. It should be
+// called before any probes are added. A size of zero or less disables the
+// log.
+func (p *Prober) WithEventLog(size int) *Prober {
+	if size <= 0 {
+		p.events = nil
+	} else {
+		p.events = newEventLog(size)
+	}
+	return p
+}
+
+// RecentEvents returns up to n of the most recent probe runs, oldest first.
+// If n is zero or negative, all retained runs are returned. It returns nil
+// if the event log is not enabled; see WithEventLog.
+func (p *Prober) RecentEvents(n int) []ProbeEvent {
+	return p.events.recent(n)
+}
+
 // WithMetricNamespace allows changing metric name prefix from the default `prober`.
 func (p *Prober) WithMetricNamespace(n string) *Prober {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.namespace = n
 	return p
 }
@@ -226,12 +340,22 @@ type Probe struct {
 	mAttempts    *prometheus.CounterVec
 	mSeconds     *prometheus.CounterVec
 
-	mu        sync.Mutex
-	start     time.Time     // last time doProbe started
-	end       time.Time     // last time doProbe returned
-	latency   time.Duration // last successful probe latency
-	succeeded bool          // whether the last doProbe call succeeded
-	lastErr   error
+	// SLO tracking state and metrics; nil if SLO tracking is disabled.
+	slo           *sloTracker
+	mSLOBudget    *prometheus.Desc
+	mSLOViolation *prometheus.Desc
+
+	mu          sync.Mutex
+	tags        []string          // user-provided tags for filtering; not metric labels
+	annotations map[string]string // user-provided annotations; not metric labels
+	start       time.Time         // last time doProbe started
+	end         time.Time         // last time doProbe returned
+	latency     time.Duration     // last successful probe latency
+	succeeded   bool              // whether the last doProbe call succeeded
+	lastErr     error
+
+	externalVerdict *bool    // verdict set by SetExternalVerdict, or nil
+	notifiedVerdict opt.Bool // last verdict passed to the notifier
 }
 
 // Close shuts down the Probe and unregisters it from its Prober.
@@ -284,7 +408,17 @@ func (p *Probe) loop() {
 // the probe either succeeds or fails before the next cycle is
 // scheduled to start.
 func (p *Probe) run() {
+	if err := p.waitRateLimit(); err != nil {
+		if p.ctx.Err() == nil {
+			log.Printf("probe %s: %v", p.name, err)
+		}
+		return
+	}
 	start := p.recordStart()
+	timeout := time.Duration(float64(p.interval) * 0.8)
+	ctx, cancel := context.WithTimeout(p.ctx, timeout)
+	defer cancel()
+	ctx, span := p.startSpan(ctx)
 	defer func() {
 		// Prevent a panic within one probe function from killing the
 		// entire prober, so that a single buggy probe doesn't destroy
@@ -293,15 +427,12 @@ func (p *Probe) run() {
 		// alert for debugging.
 		if r := recover(); r != nil {
 			log.Printf("probe %s panicked: %v", p.name, r)
-			p.recordEnd(start, errors.New("panic"))
+			p.recordEnd(start, span, errors.New("panic"))
 		}
 	}()
-	timeout := time.Duration(float64(p.interval) * 0.8)
-	ctx, cancel := context.WithTimeout(p.ctx, timeout)
-	defer cancel()
 
 	err := p.probeClass.Probe(ctx)
-	p.recordEnd(start, err)
+	p.recordEnd(start, span, err)
 	if err != nil {
 		log.Printf("probe %s: %v", p.name, err)
 	}
@@ -315,14 +446,41 @@ func (p *Probe) recordStart() time.Time {
 	return st
 }
 
-func (p *Probe) recordEnd(start time.Time, err error) {
+func (p *Probe) recordEnd(start time.Time, span Span, err error) {
+	latency, tr, changed := p.recordResult(err)
+	if span != nil {
+		span.End(latency, err)
+	}
+	if changed {
+		p.notify(tr)
+	}
+}
+
+// recordResult records the result of a probe run that just finished, and
+// returns its latency and the resulting verdict transition, if any (see
+// WithNotifier).
+func (p *Probe) recordResult(err error) (latency time.Duration, tr Transition, changed bool) {
 	end := p.prober.now()
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.end = end
 	p.succeeded = err == nil
 	p.lastErr = err
-	latency := end.Sub(p.start)
+	latency = end.Sub(p.start)
+	if p.slo != nil {
+		p.slo.add(end, p.succeeded)
+	}
+	ev := ProbeEvent{
+		Time:    end,
+		Probe:   p.name,
+		Class:   p.probeClass.Class,
+		Result:  p.succeeded,
+		Latency: latency,
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	p.prober.events.add(ev)
 	if p.succeeded {
 		p.latency = latency
 		p.mAttempts.WithLabelValues("ok").Inc()
@@ -332,15 +490,48 @@ func (p *Probe) recordEnd(start time.Time, err error) {
 		p.mAttempts.WithLabelValues("fail").Inc()
 		p.mSeconds.WithLabelValues("fail").Add(latency.Seconds())
 	}
+	tr, changed = p.updateVerdictLocked()
+	return latency, tr, changed
+}
+
+// WithTags sets tags on the probe, which can be used to filter probes in
+// StatusHandler. Unlike labels, tags are not exported as metrics.
+func (p *Probe) WithTags(tags ...string) *Probe {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tags = slices.Clone(tags)
+	return p
+}
+
+// WithAnnotations sets annotations on the probe, such as links to a
+// "runbook" or "dashboard", or a "summary" of what the probe checks. They
+// are included in ProbeInfo and in notifications (see Prober.WithNotifier).
+// Unlike labels, annotations are not exported as metrics, so they may have
+// arbitrary values without affecting metric cardinality.
+func (p *Probe) WithAnnotations(annotations map[string]string) *Probe {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.annotations = maps.Clone(annotations)
+	return p
 }
 
 // ProbeInfo is the state of a Probe.
 type ProbeInfo struct {
+	Class   string `json:",omitempty"`
 	Start   time.Time
 	End     time.Time
 	Latency string
 	Result  bool
 	Error   string
+	Tags    []string `json:",omitempty"`
+
+	// Annotations are the probe's annotations; see Probe.WithAnnotations.
+	Annotations map[string]string `json:",omitempty"`
+
+	// ExternalVerdict is the verdict set by Probe.SetExternalVerdict, if
+	// any. Unlike Result, it is not necessarily based on this prober's
+	// local result.
+	ExternalVerdict *bool `json:",omitempty"`
 }
 
 func (p *Prober) ProbeInfo() map[string]ProbeInfo {
@@ -356,9 +547,15 @@ func (p *Prober) ProbeInfo() map[string]ProbeInfo {
 	for _, probe := range probes {
 		probe.mu.Lock()
 		inf := ProbeInfo{
-			Start:  probe.start,
-			End:    probe.end,
-			Result: probe.succeeded,
+			Class:       probe.probeClass.Class,
+			Start:       probe.start,
+			End:         probe.end,
+			Result:      probe.succeeded,
+			Tags:        slices.Clone(probe.tags),
+			Annotations: maps.Clone(probe.annotations),
+		}
+		if v := probe.externalVerdict; v != nil {
+			inf.ExternalVerdict = ptr.To(*v)
 		}
 		if probe.lastErr != nil {
 			inf.Error = probe.lastErr.Error()
@@ -381,6 +578,10 @@ func (p *Probe) Describe(ch chan<- *prometheus.Desc) {
 	ch <- p.mLatency
 	p.mAttempts.Describe(ch)
 	p.mSeconds.Describe(ch)
+	if p.slo != nil {
+		ch <- p.mSLOBudget
+		ch <- p.mSLOViolation
+	}
 	if p.probeClass.Metrics != nil {
 		for _, m := range p.probeClass.Metrics(p.metricLabels) {
 			ch <- m.Desc()
@@ -410,6 +611,15 @@ func (p *Probe) Collect(ch chan<- prometheus.Metric) {
 	}
 	p.mAttempts.Collect(ch)
 	p.mSeconds.Collect(ch)
+	if p.slo != nil {
+		budget, violated := p.slo.status(p.prober.now())
+		ch <- prometheus.MustNewConstMetric(p.mSLOBudget, prometheus.GaugeValue, budget)
+		if violated {
+			ch <- prometheus.MustNewConstMetric(p.mSLOViolation, prometheus.GaugeValue, 1)
+		} else {
+			ch <- prometheus.MustNewConstMetric(p.mSLOViolation, prometheus.GaugeValue, 0)
+		}
+	}
 	if p.probeClass.Metrics != nil {
 		for _, m := range p.probeClass.Metrics(p.metricLabels) {
 			ch <- m
@@ -417,6 +627,71 @@ func (p *Probe) Collect(ch chan<- prometheus.Metric) {
 	}
 }
 
+// metaProbers are the live Probers, in creation order, whose meta metrics
+// are exported by metaCollector.
+var metaProbers struct {
+	sync.Mutex
+	list []*Prober
+}
+
+// registerMetaOnce registers metaCollector with the default registerer the
+// first time a Prober is created.
+var registerMetaOnce sync.Once
+
+func addMetaProber(p *Prober) {
+	metaProbers.Lock()
+	metaProbers.list = append(metaProbers.list, p)
+	metaProbers.Unlock()
+	registerMetaOnce.Do(func() {
+		prometheus.DefaultRegisterer.MustRegister(metaCollector{})
+	})
+}
+
+func removeMetaProber(p *Prober) {
+	metaProbers.Lock()
+	defer metaProbers.Unlock()
+	metaProbers.list = slices.DeleteFunc(metaProbers.list, func(q *Prober) bool { return q == p })
+}
+
+// metaCollector is a prometheus.Collector that exports metrics about the
+// probers in this process, so that monitoring can tell that they are alive.
+// It is registered once per process, and exports each metric once per
+// metric namespace in use, using the first Prober created with that
+// namespace, so that several Probers in one process don't export
+// conflicting copies.
+type metaCollector struct{}
+
+// Describe implements prometheus.Collector. It sends no descriptors, making
+// metaCollector an unchecked collector, because metric names depend on the
+// Probers' namespaces, which can change after registration.
+func (metaCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (metaCollector) Collect(ch chan<- prometheus.Metric) {
+	metaProbers.Lock()
+	probers := slices.Clone(metaProbers.list)
+	metaProbers.Unlock()
+	seen := map[string]bool{}
+	for _, p := range probers {
+		p.mu.Lock()
+		ns := p.namespace
+		p.mu.Unlock()
+		if seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		collectMeta(ns, p.now(), ch)
+	}
+}
+
+// collectMeta sends the meta metrics for namespace ns to ch.
+func collectMeta(ns string, now time.Time, ch chan<- prometheus.Metric) {
+	buildInfo := prometheus.NewDesc(ns+"_build_info", "Prober build information (always 1)", []string{"version"}, nil)
+	lastGather := prometheus.NewDesc(ns+"_last_gather_secs", "Time of the latest metrics gather (seconds since epoch)", nil, nil)
+	ch <- prometheus.MustNewConstMetric(buildInfo, prometheus.GaugeValue, 1, version.Long())
+	ch <- prometheus.MustNewConstMetric(lastGather, prometheus.GaugeValue, float64(now.Unix()))
+}
+
 // ticker wraps a time.Ticker in a way that can be faked for tests.
 type ticker interface {
 	Chan() <-chan time.Time
@@ -7,14 +7,18 @@
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"tailscale.com/tstest"
+	"tailscale.com/version"
 )
 
 const (
@@ -292,6 +296,121 @@ func TestOnceMode(t *testing.T) {
 	}
 }
 
+func TestRunDuplicate(t *testing.T) {
+	clk := newFakeTime()
+	p := newForTest(clk.Now, clk.NewTicker)
+	noop := FuncProbe(func(context.Context) error { return nil })
+
+	srvA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srvA.Close()
+	srvB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srvB.Close()
+
+	first := p.Run("probe", probeInterval, Labels{"region": "a"}, HTTP(srvA.URL, ""))
+	defer first.Close()
+
+	// Re-registering is an error even if only the probe target changed, as
+	// reusing the existing probe would keep probing the old target.
+	if _, err := p.TryRun("probe", probeInterval, Labels{"region": "a"}, HTTP(srvB.URL, "")); err == nil || !strings.Contains(err.Error(), "Close it before registering it again") {
+		t.Errorf("re-registering with a different URL: got error %v; want already registered", err)
+	}
+	if _, err := p.TryRun("probe", probeInterval, Labels{"region": "b"}, noop); err == nil || !strings.Contains(err.Error(), "already registered with labels") {
+		t.Errorf("conflicting labels: got error %v; want labels conflict", err)
+	}
+	if _, err := p.TryRun("probe", 2*probeInterval, Labels{"region": "a"}, HTTP(srvA.URL, "")); err == nil || !strings.Contains(err.Error(), "already registered with interval") {
+		t.Errorf("conflicting interval: got error %v; want interval conflict", err)
+	}
+	if c, err := testutil.GatherAndCount(p.metrics, "prober_interval_secs"); c != 1 || err != nil {
+		t.Errorf("got %d prober_interval_secs metrics (error %v); want 1", c, err)
+	}
+
+	// After closing the existing probe, its replacement can be registered.
+	first.Close()
+	replaced, err := p.TryRun("probe", probeInterval, Labels{"region": "a"}, HTTP(srvB.URL, ""))
+	if err != nil {
+		t.Fatalf("registering replacement after Close: %v", err)
+	}
+	defer replaced.Close()
+	if replaced == first {
+		t.Error("registering replacement after Close returned the old probe")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Run with an already-registered name did not panic")
+		}
+	}()
+	p.Run("probe", probeInterval, Labels{"region": "a"}, noop)
+}
+
+func TestMetaMetrics(t *testing.T) {
+	clk := newFakeTime()
+	p := newForTest(clk.Now, clk.NewTicker).WithMetricNamespace("metatest")
+	defer p.unregisterMetrics()
+	// The meta metrics are exported by a single collector for the whole
+	// process, not by p's own registry.
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metaCollector{})
+
+	want := fmt.Sprintf(`
+# HELP metatest_build_info Prober build information (always 1)
+# TYPE metatest_build_info gauge
+metatest_build_info{version=%q} 1
+# HELP metatest_last_gather_secs Time of the latest metrics gather (seconds since epoch)
+# TYPE metatest_last_gather_secs gauge
+metatest_last_gather_secs %d
+`, version.Long(), epoch.Unix())
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "metatest_build_info", "metatest_last_gather_secs"); err != nil {
+		t.Fatal(err)
+	}
+
+	clk.Advance(time.Minute)
+	want = fmt.Sprintf(`
+# HELP metatest_last_gather_secs Time of the latest metrics gather (seconds since epoch)
+# TYPE metatest_last_gather_secs gauge
+metatest_last_gather_secs %d
+`, epoch.Add(time.Minute).Unix())
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "metatest_last_gather_secs"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetaMetricsMultipleProbers(t *testing.T) {
+	// Simulate a fresh process, without the metrics left behind by Probers
+	// created in other tests.
+	reg := prometheus.NewRegistry()
+	oldRegisterer, oldGatherer := prometheus.DefaultRegisterer, prometheus.DefaultGatherer
+	prometheus.DefaultRegisterer, prometheus.DefaultGatherer = reg, reg
+	registerMetaOnce = sync.Once{}
+	defer func() {
+		prometheus.DefaultRegisterer, prometheus.DefaultGatherer = oldRegisterer, oldGatherer
+	}()
+
+	p1, p2 := New(), New()
+	defer p1.unregisterMetrics()
+	defer p2.unregisterMetrics()
+	if _, err := RunProbesOnce(context.Background(), []ProbeSpec{{
+		Name:  "ok",
+		Class: FuncProbe(func(context.Context) error { return nil }),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gathering default registry with several probers: %v", err)
+	}
+	var found int
+	for _, mf := range mfs {
+		if mf.GetName() == "prober_build_info" {
+			found = len(mf.GetMetric())
+		}
+	}
+	if found != 1 {
+		t.Errorf("got %d prober_build_info metrics; want 1", found)
+	}
+}
+
 type fakeTicker struct {
 	ch       chan time.Time
 	interval time.Duration
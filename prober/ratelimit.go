@@ -0,0 +1,49 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"errors"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimiter makes every probe run wait for a token from lim before
+// executing. The same limiter may be shared by several Probers, such as
+// when they probe the same downstream service, to smooth their aggregate
+// load. Time spent waiting for a token is not counted as probe latency.
+// lim must have a burst of at least one. It should be set before any
+// probes are added.
+func (p *Prober) WithRateLimiter(lim *rate.Limiter) *Prober {
+	p.limiter = lim
+	return p
+}
+
+// waitRateLimit blocks until the prober's rate limiter, if any, allows the
+// probe to run. It returns an error if the probe is shut down while waiting
+// or if the limiter can never allow a run.
+func (p *Probe) waitRateLimit() error {
+	lim := p.prober.limiter
+	if lim == nil {
+		return nil
+	}
+	now := p.prober.now()
+	r := lim.ReserveN(now, 1)
+	if !r.OK() {
+		return errors.New("rate limiter does not allow any probe runs")
+	}
+	d := r.DelayFrom(now)
+	if d <= 0 {
+		return nil
+	}
+	t := p.prober.newTicker(d)
+	defer t.Stop()
+	select {
+	case <-t.Chan():
+		return nil
+	case <-p.ctx.Done():
+		r.CancelAt(p.prober.now())
+		return p.ctx.Err()
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+	"tailscale.com/tstest"
+)
+
+func TestRateLimiter(t *testing.T) {
+	clk := newFakeTime()
+	lim := rate.NewLimiter(rate.Every(time.Second), 1)
+
+	// Two probers share the limiter.
+	p1 := newForTest(clk.Now, clk.NewTicker).WithOnce(true).WithRateLimiter(lim)
+	p2 := newForTest(clk.Now, clk.NewTicker).WithOnce(true).WithRateLimiter(lim)
+
+	var runs atomic.Int32
+	probe := FuncProbe(func(context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	wantRuns := func(want int32) {
+		t.Helper()
+		err := tstest.WaitFor(convergenceTimeout, func() error {
+			if got := runs.Load(); got != want {
+				return fmt.Errorf("got %d probe runs; want %d", got, want)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p1.Run("a", probeInterval, nil, probe)
+	p2.Run("b", probeInterval, nil, probe)
+	p2.Run("c", probeInterval, nil, probe)
+
+	// One probe gets the only token; the other two wait on the limiter.
+	wantRuns(1)
+	if err := tstest.WaitFor(convergenceTimeout, func() error {
+		if n := clk.activeTickers(); n != 2 {
+			return fmt.Errorf("got %d probes waiting on limiter; want 2", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("got %d runs with exhausted limiter; want 1", got)
+	}
+
+	clk.Advance(time.Second + aFewMillis)
+	wantRuns(2)
+	clk.Advance(time.Second)
+	wantRuns(3)
+
+	p1.Wait()
+	p2.Wait()
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultSpecTimeout is the probe timeout used by RunProbesOnce for specs
+// that don't set one.
+const defaultSpecTimeout = 30 * time.Second
+
+// ProbeSpec describes a probe to run with RunProbesOnce.
+type ProbeSpec struct {
+	Name   string
+	Class  ProbeClass
+	Labels Labels
+
+	// Timeout bounds the probe run. If zero, it defaults to 30 seconds.
+	Timeout time.Duration
+}
+
+// ProbeResult is the result of a probe run by RunProbesOnce.
+type ProbeResult struct {
+	Name    string
+	Class   string
+	Result  bool          // whether the probe succeeded
+	Latency time.Duration // how long the probe took to run
+	Error   string        `json:",omitempty"` // error returned by the probe, if it failed
+}
+
+// RunProbesOnce runs each probe in specs once, concurrently, and returns
+// their results in the same order as specs. It's intended for using probes
+// as a library, such as in CI smoke tests, without managing a Prober. The
+// probes' metrics are not exported by the default Prometheus registerer.
+//
+// The returned error is non-nil if any probe failed, and combines the
+// errors of all failed probes. If ctx is done before all probes finish,
+// the remaining probes are canceled and ctx.Err() is returned along with
+// nil results.
+func RunProbesOnce(ctx context.Context, specs []ProbeSpec) (results []ProbeResult, err error) {
+	return runProbesOnce(ctx, newUnregistered(time.Now, newRealTicker), specs)
+}
+
+func runProbesOnce(ctx context.Context, p *Prober, specs []ProbeSpec) (results []ProbeResult, err error) {
+	p.WithOnce(true)
+
+	probes := make([]*Probe, 0, len(specs))
+	defer func() {
+		for _, probe := range probes {
+			probe.Close()
+		}
+	}()
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("duplicate probe name %q", spec.Name)
+		}
+		seen[spec.Name] = true
+		timeout := spec.Timeout
+		if timeout == 0 {
+			timeout = defaultSpecTimeout
+		}
+		// Probe.run times out probes after 80% of their interval.
+		probe, err := p.TryRun(spec.Name, timeout*5/4, spec.Labels, spec.Class)
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, probe)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, probe := range probes {
+			<-probe.stopped
+		}
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var errs []error
+	for _, probe := range probes {
+		probe.mu.Lock()
+		r := ProbeResult{
+			Name:    probe.name,
+			Class:   probe.probeClass.Class,
+			Result:  probe.succeeded,
+			Latency: probe.end.Sub(probe.start),
+		}
+		if probe.lastErr != nil {
+			r.Error = probe.lastErr.Error()
+			errs = append(errs, fmt.Errorf("probe %q: %w", probe.name, probe.lastErr))
+		}
+		probe.mu.Unlock()
+		results = append(results, r)
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,143 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRunProbesOnce(t *testing.T) {
+	clk := newFakeTime()
+	p := newUnregistered(clk.Now, clk.NewTicker)
+
+	results, err := runProbesOnce(context.Background(), p, []ProbeSpec{
+		{
+			Name:  "ok",
+			Class: ProbeClass{Class: "test", Probe: func(context.Context) error { return nil }},
+		},
+		{
+			Name: "fail",
+			Class: FuncProbe(func(context.Context) error {
+				return errors.New("boom")
+			}),
+		},
+		{
+			Name:  "panic",
+			Class: FuncProbe(func(context.Context) error { panic("oops") }),
+		},
+	})
+	if err == nil {
+		t.Fatal("got nil error; want failures")
+	}
+	for _, want := range []string{`probe "fail": boom`, `probe "panic": panic`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), `"ok"`) {
+		t.Errorf("error %q mentions passing probe", err)
+	}
+	want := []ProbeResult{
+		{Name: "ok", Class: "test", Result: true},
+		{Name: "fail", Result: false, Error: "boom"},
+		{Name: "panic", Result: false, Error: "panic"},
+	}
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Errorf("results mismatch (-want +got):\n%s", diff)
+	}
+	if got := p.activeProbes(); got != 0 {
+		t.Errorf("%d probes still registered after RunProbesOnce", got)
+	}
+}
+
+func TestRunProbesOncePassing(t *testing.T) {
+	results, err := RunProbesOnce(context.Background(), []ProbeSpec{
+		{Name: "a", Class: FuncProbe(func(context.Context) error { return nil })},
+		{Name: "b", Class: FuncProbe(func(context.Context) error { return nil })},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Name != "a" || results[1].Name != "b" {
+		t.Fatalf("got results %+v; want a and b in order", results)
+	}
+	for _, r := range results {
+		if !r.Result || r.Error != "" {
+			t.Errorf("probe %q: got %+v; want success", r.Name, r)
+		}
+	}
+}
+
+func TestRunProbesOnceCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err := RunProbesOnce(ctx, []ProbeSpec{{
+		Name:    "slow",
+		Timeout: time.Hour,
+		Class: FuncProbe(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestRunProbesOnceConcurrent(t *testing.T) {
+	// Use a fresh default registry, without the metrics left behind by
+	// Probers created in other tests.
+	reg := prometheus.NewRegistry()
+	oldRegisterer, oldGatherer := prometheus.DefaultRegisterer, prometheus.DefaultGatherer
+	prometheus.DefaultRegisterer, prometheus.DefaultGatherer = reg, reg
+	defer func() {
+		prometheus.DefaultRegisterer, prometheus.DefaultGatherer = oldRegisterer, oldGatherer
+	}()
+
+	// Both calls run a probe of the same name at the same time, and gather
+	// the default registry while it's running.
+	var started sync.WaitGroup
+	started.Add(2)
+	spec := ProbeSpec{
+		Name: "same",
+		Class: FuncProbe(func(context.Context) error {
+			started.Done()
+			started.Wait()
+			mfs, err := prometheus.DefaultGatherer.Gather()
+			if err != nil {
+				return err
+			}
+			if len(mfs) != 0 {
+				return fmt.Errorf("default registry has %d metric families; want 0", len(mfs))
+			}
+			return nil
+		}),
+	}
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := RunProbesOnce(context.Background(), []ProbeSpec{spec})
+			errs <- err
+		}()
+	}
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
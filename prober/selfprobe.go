@@ -0,0 +1,134 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// selfProbe tracks whether the Prober's tickers fire on schedule, as a
+// proxy for whether probe scheduling is starved.
+//
+// Its fields other than stop and done are protected by the owning Prober's
+// mu.
+type selfProbe struct {
+	interval time.Duration // expected time between ticks
+	maxLag   time.Duration // largest tolerated scheduling delay
+
+	stop chan struct{} // closed to stop the self-probe loop
+	done chan struct{} // closed when the self-probe loop has returned
+
+	lastTick time.Time     // when the latest tick was serviced
+	lag      time.Duration // how late the latest tick was serviced
+}
+
+// WithSelfProbe enables an internal probe that checks that a ticker with
+// the given interval fires on schedule, and exports whether it does as the
+// <namespace>_self_healthy metric. The Prober is considered unhealthy if a
+// tick is serviced more than maxLag later than expected, or if no tick has
+// been serviced for longer than interval plus maxLag.
+//
+// Calling it again replaces the previous self-probe, and calling it with a
+// zero interval disables self-probing, stopping its ticker and goroutine.
+// It has no effect in once mode.
+func (p *Prober) WithSelfProbe(interval, maxLag time.Duration) *Prober {
+	if p.once {
+		return p
+	}
+	var s *selfProbe
+	if interval > 0 {
+		s = &selfProbe{
+			interval: interval,
+			maxLag:   maxLag,
+			stop:     make(chan struct{}),
+			done:     make(chan struct{}),
+			lastTick: p.now(),
+		}
+	}
+	p.mu.Lock()
+	old := p.self
+	p.self = s
+	p.mu.Unlock()
+	if old != nil {
+		close(old.stop)
+		<-old.done
+	}
+	if s != nil {
+		go p.selfProbeLoop(s, p.newTicker(interval))
+	}
+	return p
+}
+
+func (p *Prober) selfProbeLoop(s *selfProbe, t ticker) {
+	defer close(s.done)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.Chan():
+		}
+		now := p.now()
+		p.mu.Lock()
+		expected := s.lastTick.Add(s.interval)
+		s.lag = max(now.Sub(expected), 0)
+		s.lastTick = now
+		p.mu.Unlock()
+	}
+}
+
+// healthy reports whether ticks are being serviced on schedule as of now.
+// p.mu must be held.
+func (s *selfProbe) healthy(now time.Time) bool {
+	if s.lag > s.maxLag {
+		return false
+	}
+	return now.Sub(s.lastTick) <= s.interval+s.maxLag
+}
+
+// selfCollector is a prometheus.Collector that exports a Prober's
+// self-probe metrics, if enabled with WithSelfProbe.
+type selfCollector struct {
+	p *Prober
+}
+
+// Describe implements prometheus.Collector. It sends no descriptors, making
+// selfCollector an unchecked collector, because metric names depend on the
+// Prober's namespace, which can change after registration.
+func (selfCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c selfCollector) Collect(ch chan<- prometheus.Metric) {
+	c.p.mu.Lock()
+	ns := c.p.namespace
+	c.p.mu.Unlock()
+	c.p.collectSelfProbe(ns, ch)
+}
+
+// collectSelfProbe sends the self-probe metrics to ch, if the self-probe is
+// enabled, using namespace ns for the metric names.
+func (p *Prober) collectSelfProbe(ns string, ch chan<- prometheus.Metric) {
+	now := p.now()
+	p.mu.Lock()
+	s := p.self
+	var healthy bool
+	var lag time.Duration
+	if s != nil {
+		healthy, lag = s.healthy(now), s.lag
+	}
+	p.mu.Unlock()
+	if s == nil {
+		return
+	}
+	var v float64
+	if healthy {
+		v = 1
+	}
+	healthyDesc := prometheus.NewDesc(ns+"_self_healthy", "Whether probe scheduling is on time (1 = healthy, 0 = lagging)", nil, nil)
+	lagDesc := prometheus.NewDesc(ns+"_self_lag_secs", "How late the latest self-probe tick was serviced, in seconds", nil, nil)
+	ch <- prometheus.MustNewConstMetric(healthyDesc, prometheus.GaugeValue, v)
+	ch <- prometheus.MustNewConstMetric(lagDesc, prometheus.GaugeValue, lag.Seconds())
+}
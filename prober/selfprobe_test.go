@@ -0,0 +1,97 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"tailscale.com/tstest"
+)
+
+func TestSelfProbe(t *testing.T) {
+	clk := newFakeTime()
+	maxLag := quarterProbeInterval
+	p := newForTest(clk.Now, clk.NewTicker).WithSelfProbe(probeInterval, maxLag)
+	defer p.WithSelfProbe(0, 0)
+
+	wantSelf := func(healthy float64, lag time.Duration) {
+		t.Helper()
+		err := tstest.WaitFor(convergenceTimeout, func() error {
+			mfs, err := p.metrics.Gather()
+			if err != nil {
+				return err
+			}
+			got := map[string]float64{}
+			for _, mf := range mfs {
+				for _, m := range mf.GetMetric() {
+					got[mf.GetName()] = m.GetGauge().GetValue()
+				}
+			}
+			gotHealthy, gotLag := got["prober_self_healthy"], got["prober_self_lag_secs"]
+			if gotHealthy != healthy || gotLag != lag.Seconds() {
+				return fmt.Errorf("got healthy=%v lag=%v; want healthy=%v lag=%v", gotHealthy, gotLag, healthy, lag.Seconds())
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wantSelf(1, 0)
+
+	// A tick serviced slightly late is still healthy.
+	clk.Advance(probeInterval + aFewMillis)
+	wantSelf(1, aFewMillis)
+
+	// Ticks that aren't serviced for several intervals mark the scheduler
+	// as lagging.
+	clk.Advance(3 * probeInterval)
+	wantSelf(0, 2*probeInterval)
+
+	// Once ticks are on time again, it recovers.
+	clk.Advance(probeInterval + aFewMillis)
+	wantSelf(1, aFewMillis)
+}
+
+func TestSelfProbeDisable(t *testing.T) {
+	clk := newFakeTime()
+	p := newForTest(clk.Now, clk.NewTicker).WithSelfProbe(probeInterval, quarterProbeInterval)
+	if got := clk.activeTickers(); got != 1 {
+		t.Fatalf("%d active tickers with self-probe enabled; want 1", got)
+	}
+
+	// Replacing the self-probe stops the previous ticker.
+	p.WithSelfProbe(2*probeInterval, quarterProbeInterval)
+	if got := clk.activeTickers(); got != 1 {
+		t.Errorf("%d active tickers after replacing self-probe; want 1", got)
+	}
+
+	p.WithSelfProbe(0, 0)
+	if got := clk.activeTickers(); got != 0 {
+		t.Errorf("%d active tickers with self-probe disabled; want 0", got)
+	}
+	mfs, err := p.metrics.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if strings.HasPrefix(mf.GetName(), "prober_self_") {
+			t.Errorf("got metric %s with self-probe disabled", mf.GetName())
+		}
+	}
+}
+
+func TestSelfProbeStale(t *testing.T) {
+	s := &selfProbe{interval: probeInterval, maxLag: quarterProbeInterval, lastTick: epoch}
+	if !s.healthy(epoch.Add(probeInterval + quarterProbeInterval)) {
+		t.Error("unhealthy at the deadline for the next tick")
+	}
+	if s.healthy(epoch.Add(probeInterval + quarterProbeInterval + time.Millisecond)) {
+		t.Error("healthy after the next tick was overdue")
+	}
+}
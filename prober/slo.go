@@ -0,0 +1,66 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import "time"
+
+// sloTracker tracks probe results over a rolling time window and computes
+// the remaining error budget against a target success ratio.
+//
+// It is not safe for concurrent use; callers must hold the owning Probe's mu.
+type sloTracker struct {
+	target float64       // target success ratio, in [0, 1]
+	window time.Duration // length of the rolling window
+
+	results []sloResult // results within the window, oldest first
+}
+
+type sloResult struct {
+	at time.Time
+	ok bool
+}
+
+// add records a probe result at the given time.
+func (s *sloTracker) add(at time.Time, ok bool) {
+	s.results = append(s.results, sloResult{at, ok})
+	s.prune(at)
+}
+
+// prune drops results that are no longer within the window ending at now.
+func (s *sloTracker) prune(now time.Time) {
+	cutoff := now.Add(-s.window)
+	i := 0
+	for i < len(s.results) && !s.results[i].at.After(cutoff) {
+		i++
+	}
+	s.results = s.results[i:]
+}
+
+// status returns the fraction of the error budget remaining over the window
+// ending at now, and whether the success ratio is below the target.
+//
+// The budget is 1 when there are no failures (or no results at all) and 0
+// when the number of failures meets or exceeds what the target allows.
+func (s *sloTracker) status(now time.Time) (budget float64, violated bool) {
+	s.prune(now)
+	total := len(s.results)
+	if total == 0 {
+		return 1, false
+	}
+	var failed int
+	for _, r := range s.results {
+		if !r.ok {
+			failed++
+		}
+	}
+	violated = float64(total-failed)/float64(total) < s.target
+	allowed := (1 - s.target) * float64(total)
+	if allowed <= 0 {
+		if failed == 0 {
+			return 1, violated
+		}
+		return 0, violated
+	}
+	return max(0, 1-float64(failed)/allowed), violated
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"tailscale.com/tstest"
+)
+
+func TestSLOTracker(t *testing.T) {
+	s := &sloTracker{target: 0.5, window: 10 * time.Second}
+	now := epoch
+
+	type step struct {
+		ok           bool
+		wantBudget   float64
+		wantViolated bool
+	}
+	steps := []step{
+		{ok: true, wantBudget: 1},
+		{ok: true, wantBudget: 1},
+		{ok: true, wantBudget: 1},
+		{ok: false, wantBudget: 0.5},                   // 1 of 2 allowed failures
+		{ok: false, wantBudget: 0.2},                   // 2 of 2.5 allowed failures
+		{ok: false, wantBudget: 0},                     // 3 of 3 allowed failures
+		{ok: false, wantBudget: 0, wantViolated: true}, // 4 of 7 failed
+	}
+	for i, st := range steps {
+		now = now.Add(time.Second)
+		s.add(now, st.ok)
+		budget, violated := s.status(now)
+		if fmt.Sprintf("%.3f", budget) != fmt.Sprintf("%.3f", st.wantBudget) || violated != st.wantViolated {
+			t.Errorf("step %d: got (%v, %v); want (%v, %v)", i, budget, violated, st.wantBudget, st.wantViolated)
+		}
+	}
+
+	// Once the failures fall out of the window, the budget is restored.
+	now = now.Add(10 * time.Second)
+	s.add(now, true)
+	if budget, violated := s.status(now); budget != 1 || violated {
+		t.Errorf("after window: got (%v, %v); want (1, false)", budget, violated)
+	}
+	if budget, violated := s.status(now.Add(time.Hour)); budget != 1 || violated {
+		t.Errorf("empty window: got (%v, %v); want (1, false)", budget, violated)
+	}
+}
+
+func TestSLOMetrics(t *testing.T) {
+	clk := newFakeTime()
+	p := newForTest(clk.Now, clk.NewTicker).WithSLO(0.5, 3*probeInterval)
+
+	var succeed atomic.Bool
+	p.Run("testprobe", probeInterval, nil, FuncProbe(func(context.Context) error {
+		if succeed.Load() {
+			return nil
+		}
+		return errors.New("failing, as instructed by test")
+	}))
+	waitActiveProbes(t, p, clk, 1)
+
+	wantSLO := func(budget, violation float64) {
+		t.Helper()
+		err := tstest.WaitFor(convergenceTimeout, func() error {
+			mfs, err := p.metrics.Gather()
+			if err != nil {
+				return err
+			}
+			got := map[string]float64{}
+			for _, mf := range mfs {
+				for _, m := range mf.GetMetric() {
+					got[mf.GetName()] = m.GetGauge().GetValue()
+				}
+			}
+			gotBudget, gotViolation := got["prober_slo_budget_remaining"], got["prober_slo_violation"]
+			if math.Abs(gotBudget-budget) > 1e-9 || gotViolation != violation {
+				return fmt.Errorf("got budget=%v violation=%v; want budget=%v violation=%v", gotBudget, gotViolation, budget, violation)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first run fails.
+	wantSLO(0, 1)
+
+	// Two successes bring the success ratio back to the target.
+	succeed.Store(true)
+	clk.Advance(probeInterval + halfProbeInterval)
+	wantSLO(0, 0)
+	clk.Advance(probeInterval)
+	wantSLO(1.0/3, 0)
+
+	// The failure falls out of the window as time passes.
+	clk.Advance(probeInterval)
+	wantSLO(1, 0)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// StatusHandler returns an HTTP handler that serves the state of all probes
+// as a JSON object mapping probe names to their ProbeInfo.
+//
+// The result can be filtered with query parameters:
+//
+//   - tag=T: only include probes tagged with T (see Probe.WithTags).
+//     If repeated, probes must have all of the given tags.
+//   - failing=true: only include probes whose latest run failed.
+//     failing=false only includes probes whose latest run succeeded.
+//     Probes that have not finished a run yet are excluded either way.
+func (p *Prober) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		tags := q["tag"]
+		var failing, filterFailing bool
+		if v := q.Get("failing"); v != "" {
+			var err error
+			failing, err = strconv.ParseBool(v)
+			if err != nil {
+				http.Error(w, "invalid failing parameter: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			filterFailing = true
+		}
+
+		out := map[string]ProbeInfo{}
+		for name, info := range p.ProbeInfo() {
+			if !hasAllTags(info.Tags, tags) {
+				continue
+			}
+			if filterFailing && (info.End.IsZero() || info.Result == failing) {
+				continue
+			}
+			out[name] = info
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(out); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// ReadyByClass returns an HTTP handler for readiness checks of the probes of
+// the given class, such as "derp". It responds with 200 OK if all probes of
+// the class passed their latest run, and 503 Service Unavailable if any
+// failed, if any have not finished a run yet, or if there are no probes of
+// the class. This lets separate readiness checks gate on different
+// subsystems, e.g. by serving it at /ready/derp.
+func (p *Prober) ReadyByClass(class string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n int
+		var notReady []string
+		for name, info := range p.ProbeInfo() {
+			if info.Class != class {
+				continue
+			}
+			n++
+			switch {
+			case info.End.IsZero():
+				notReady = append(notReady, name+": no result yet")
+			case !info.Result:
+				notReady = append(notReady, name+": "+info.Error)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if n == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "no probes of class %q\n", class)
+			return
+		}
+		if len(notReady) > 0 {
+			slices.Sort(notReady)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "%d of %d %q probes not passing:\n", len(notReady), n, class)
+			for _, s := range notReady {
+				fmt.Fprintf(w, "%s\n", s)
+			}
+			return
+		}
+		fmt.Fprintf(w, "all %d %q probes passing\n", n, class)
+	})
+}
+
+// hasAllTags reports whether have contains every tag in want.
+func hasAllTags(have, want []string) bool {
+	for _, t := range want {
+		if !slices.Contains(have, t) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/maps"
+)
+
+func TestStatusHandler(t *testing.T) {
+	clk := newFakeTime()
+	p := newForTest(clk.Now, clk.NewTicker).WithOnce(true)
+
+	pass := FuncProbe(func(context.Context) error { return nil })
+	fail := FuncProbe(func(context.Context) error { return errors.New("failed") })
+	p.Run("derp-ok", probeInterval, nil, pass).WithTags("derp", "eu")
+	p.Run("derp-bad", probeInterval, nil, fail).WithTags("derp")
+	p.Run("dns-bad", probeInterval, nil, fail).WithTags("dns")
+	p.Run("untagged", probeInterval, nil, pass)
+	p.Wait()
+
+	tests := []struct {
+		query    string
+		want     []string
+		wantCode int
+	}{
+		{query: "", want: []string{"derp-bad", "derp-ok", "dns-bad", "untagged"}},
+		{query: "?tag=derp", want: []string{"derp-bad", "derp-ok"}},
+		{query: "?tag=derp&tag=eu", want: []string{"derp-ok"}},
+		{query: "?tag=nope", want: []string{}},
+		{query: "?failing=true", want: []string{"derp-bad", "dns-bad"}},
+		{query: "?failing=false", want: []string{"derp-ok", "untagged"}},
+		{query: "?tag=derp&failing=true", want: []string{"derp-bad"}},
+		{query: "?failing=maybe", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			p.StatusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/status"+tt.query, nil))
+			wantCode := tt.wantCode
+			if wantCode == 0 {
+				wantCode = http.StatusOK
+			}
+			if rec.Code != wantCode {
+				t.Fatalf("status code = %d; want %d", rec.Code, wantCode)
+			}
+			if wantCode != http.StatusOK {
+				return
+			}
+			var got map[string]ProbeInfo
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			names := maps.Keys(got)
+			slices.Sort(names)
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("got probes %q; want %q", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadyByClass(t *testing.T) {
+	clk := newFakeTime()
+	p := newForTest(clk.Now, clk.NewTicker).WithOnce(true)
+
+	pass := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("failed") }
+	p.Run("derp-1", probeInterval, nil, ProbeClass{Class: "derp", Probe: pass})
+	p.Run("derp-2", probeInterval, nil, ProbeClass{Class: "derp", Probe: pass})
+	p.Run("dns-1", probeInterval, nil, ProbeClass{Class: "dns", Probe: pass})
+	p.Run("dns-2", probeInterval, nil, ProbeClass{Class: "dns", Probe: fail})
+	p.Wait()
+
+	tests := []struct {
+		class    string
+		wantCode int
+		wantBody string
+	}{
+		{"derp", http.StatusOK, "all 2"},
+		{"dns", http.StatusServiceUnavailable, "dns-2: failed"},
+		{"tls", http.StatusServiceUnavailable, "no probes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.class, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			p.ReadyByClass(tt.class).ServeHTTP(rec, httptest.NewRequest("GET", "/ready/"+tt.class, nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d; want %d", rec.Code, tt.wantCode)
+			}
+			if body := rec.Body.String(); !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body = %q; want it to contain %q", body, tt.wantBody)
+			}
+		})
+	}
+}
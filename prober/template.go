@@ -0,0 +1,76 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// validLabelName matches valid Prometheus label names.
+var validLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// RunTemplate runs a probe for each set of parameters in params, as with
+// Run. Each probe's name is nameTmpl, a text/template such as
+// "http-{{.region}}", executed with the probe's parameters; its labels are
+// the parameters themselves; and its ProbeClass is returned by factory
+// given those parameters.
+//
+// All names and labels are validated before any probe is started: names
+// must be non-empty and unique, parameter names must be valid Prometheus
+// label names other than "name" and "class", and templates must not refer
+// to missing parameters. If any probe fails to start, including because a
+// probe of the same name is already registered, the probes started by this
+// call are closed and an error is returned; previously registered probes
+// are left running.
+func (p *Prober) RunTemplate(nameTmpl string, params []map[string]string, interval time.Duration, factory func(map[string]string) ProbeClass) ([]*Probe, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(nameTmpl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing probe name template: %w", err)
+	}
+
+	names := make([]string, len(params))
+	seen := make(map[string]bool)
+	for i, ps := range params {
+		for k := range ps {
+			if !validLabelName.MatchString(k) {
+				return nil, fmt.Errorf("parameter %q is not a valid label name", k)
+			}
+			if k == "name" || k == "class" {
+				return nil, fmt.Errorf("parameter %q conflicts with a built-in label", k)
+			}
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, ps); err != nil {
+			return nil, fmt.Errorf("expanding probe name template with %v: %w", ps, err)
+		}
+		name := sb.String()
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("probe name template expands to empty name with %v", ps)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("probe name template expands to duplicate name %q", name)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+
+	// TryRun never returns an already-registered probe, so every probe in
+	// created was started by this call and is safe to close on failure.
+	created := make([]*Probe, 0, len(params))
+	for i, ps := range params {
+		probe, err := p.TryRun(names[i], interval, Labels(ps), factory(ps))
+		if err != nil {
+			for _, probe := range created {
+				probe.Close()
+			}
+			return nil, err
+		}
+		created = append(created, probe)
+	}
+	return created, nil
+}
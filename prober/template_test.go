@@ -0,0 +1,106 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"maps"
+	"testing"
+)
+
+func TestRunTemplate(t *testing.T) {
+	clk := newFakeTime()
+	p := newForTest(clk.Now, clk.NewTicker)
+
+	params := []map[string]string{
+		{"Region": "nyc", "tier": "prod"},
+		{"Region": "sfo", "tier": "prod"},
+		{"Region": "sfo", "tier": "dev"},
+	}
+	var gotParams []map[string]string
+	probes, err := p.RunTemplate("http-{{.Region}}-{{.tier}}", params, probeInterval, func(ps map[string]string) ProbeClass {
+		gotParams = append(gotParams, ps)
+		return ProbeClass{Class: "http", Probe: func(context.Context) error { return nil }}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitActiveProbes(t, p, clk, 3)
+
+	wantNames := []string{"http-nyc-prod", "http-sfo-prod", "http-sfo-dev"}
+	if len(probes) != len(wantNames) {
+		t.Fatalf("got %d probes; want %d", len(probes), len(wantNames))
+	}
+	for i, probe := range probes {
+		if probe.name != wantNames[i] {
+			t.Errorf("probe %d name = %q; want %q", i, probe.name, wantNames[i])
+		}
+		want := Labels{"name": wantNames[i], "class": "http"}
+		maps.Copy(want, params[i])
+		if !maps.Equal(probe.metricLabels, want) {
+			t.Errorf("probe %q labels = %v; want %v", probe.name, probe.metricLabels, want)
+		}
+		if !maps.Equal(gotParams[i], params[i]) {
+			t.Errorf("factory call %d got params %v; want %v", i, gotParams[i], params[i])
+		}
+	}
+	for _, probe := range probes {
+		probe.Close()
+	}
+
+	factory := func(map[string]string) ProbeClass {
+		return FuncProbe(func(context.Context) error { return nil })
+	}
+	for _, tt := range []struct {
+		name   string
+		tmpl   string
+		params []map[string]string
+	}{
+		{"duplicate", "http-{{.Region}}", params},
+		{"missing-param", "http-{{.Zone}}", params},
+		{"empty-name", "{{.Region}}", []map[string]string{{"Region": ""}}},
+		{"bad-label", "http-{{.region}}", []map[string]string{{"region": "nyc", "bad-label": "x"}}},
+		{"reserved-label", "http-{{.region}}", []map[string]string{{"region": "nyc", "class": "x"}}},
+		{"bad-template", "http-{{.Region", params},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.RunTemplate(tt.tmpl, tt.params, probeInterval, factory); err == nil {
+				t.Error("RunTemplate succeeded; want error")
+			}
+			if n := p.activeProbes(); n != 0 {
+				t.Errorf("%d probes active after failed RunTemplate; want 0", n)
+			}
+		})
+	}
+}
+
+func TestRunTemplateExisting(t *testing.T) {
+	clk := newFakeTime()
+	p := newForTest(clk.Now, clk.NewTicker)
+	factory := func(map[string]string) ProbeClass {
+		return FuncProbe(func(context.Context) error { return nil })
+	}
+
+	existingA := p.Run("http-a", probeInterval, nil, FuncProbe(func(context.Context) error { return nil }))
+	defer existingA.Close()
+	existingC := p.Run("http-c", probeInterval, nil, FuncProbe(func(context.Context) error { return nil }))
+	defer existingC.Close()
+
+	// http-b is started, then http-c fails as it's already registered.
+	params := []map[string]string{{"r": "b"}, {"r": "c"}}
+	if _, err := p.RunTemplate("http-{{.r}}", params, probeInterval, factory); err == nil {
+		t.Fatal("RunTemplate succeeded; want error")
+	}
+	p.mu.Lock()
+	_, gotA := p.probes["http-a"]
+	_, gotB := p.probes["http-b"]
+	_, gotC := p.probes["http-c"]
+	p.mu.Unlock()
+	if !gotA || !gotC {
+		t.Errorf("existing probes closed by failed RunTemplate: http-a registered = %v, http-c registered = %v", gotA, gotC)
+	}
+	if gotB {
+		t.Error("probe http-b started by failed RunTemplate is still registered")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// Tracer starts a trace span for each probe run. It is deliberately a small
+// subset of an OpenTelemetry tracer, so that the prober package does not
+// depend on OpenTelemetry; users who want probe runs exported as OTel spans
+// can implement it with a few lines wrapping their trace.TracerProvider.
+type Tracer interface {
+	// Start starts a span with the given name and attributes, and returns
+	// a context carrying it. The returned context is passed to the probe
+	// function, so that it can start child spans.
+	Start(ctx context.Context, name string, attrs map[string]string) (context.Context, Span)
+}
+
+// Span is a trace span started by a Tracer for a single probe run.
+type Span interface {
+	// End ends the span once the probe run has finished. err is the
+	// error returned by the probe function, or nil if it succeeded, and
+	// latency is the run's latency as recorded in the probe's metrics.
+	End(latency time.Duration, err error)
+}
+
+// WithTracer sets a Tracer used to start a span for each probe run. Spans
+// are named "<class>/<name>" after the probe (or just the probe name for
+// probes without a class), and have the probe's metric labels, including
+// "name" and "class", as attributes. It should be set before any probes
+// are added.
+func (p *Prober) WithTracer(t Tracer) *Prober {
+	p.tracer = t
+	return p
+}
+
+// startSpan starts a span for a probe run if the prober has a Tracer, and
+// returns the context to pass to the probe function. The returned Span is
+// nil if tracing is disabled.
+func (p *Probe) startSpan(ctx context.Context) (context.Context, Span) {
+	t := p.prober.tracer
+	if t == nil {
+		return ctx, nil
+	}
+	name := p.name
+	if c := p.probeClass.Class; c != "" {
+		name = c + "/" + name
+	}
+	return t.Start(ctx, name, maps.Clone(p.metricLabels))
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSpanKey struct{}
+
+// testSpan is a Span recorded by testTracer.
+type testSpan struct {
+	name    string
+	attrs   map[string]string
+	ended   bool
+	latency time.Duration
+	err     error
+}
+
+func (s *testSpan) End(latency time.Duration, err error) {
+	s.ended = true
+	s.latency = latency
+	s.err = err
+}
+
+// testTracer is an in-memory Tracer that records all spans it starts.
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs map[string]string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &testSpan{name: name, attrs: attrs}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, testSpanKey{}, s), s
+}
+
+func TestTracer(t *testing.T) {
+	clk := newFakeTime()
+	tr := &testTracer{}
+	p := newForTest(clk.Now, clk.NewTicker).WithOnce(true).WithTracer(tr)
+
+	var sawSpan bool
+	p.Run("ok", probeInterval, Labels{"region": "nyc"}, ProbeClass{
+		Class: "http",
+		Probe: func(ctx context.Context) error {
+			_, sawSpan = ctx.Value(testSpanKey{}).(*testSpan)
+			clk.Advance(aFewMillis)
+			return nil
+		},
+	})
+	p.Wait()
+	p.Run("fail", probeInterval, Labels{"region": "nyc"}, FuncProbe(func(context.Context) error {
+		return errors.New("boom")
+	}))
+	p.Wait()
+
+	if !sawSpan {
+		t.Error("probe context did not carry the span")
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if len(tr.spans) != 2 {
+		t.Fatalf("got %d spans; want 2", len(tr.spans))
+	}
+
+	ok := tr.spans[0]
+	if ok.name != "http/ok" {
+		t.Errorf("span name = %q; want %q", ok.name, "http/ok")
+	}
+	wantAttrs := map[string]string{"name": "ok", "class": "http", "region": "nyc"}
+	if !maps.Equal(ok.attrs, wantAttrs) {
+		t.Errorf("span attrs = %v; want %v", ok.attrs, wantAttrs)
+	}
+	if !ok.ended || ok.err != nil || ok.latency != aFewMillis {
+		t.Errorf("span ended=%v err=%v latency=%v; want ended with no error after %v", ok.ended, ok.err, ok.latency, aFewMillis)
+	}
+
+	fail := tr.spans[1]
+	if fail.name != "fail" {
+		t.Errorf("span name = %q; want %q", fail.name, "fail")
+	}
+	if !fail.ended || fail.err == nil {
+		t.Errorf("span ended=%v err=%v; want ended with error", fail.ended, fail.err)
+	}
+}
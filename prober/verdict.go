@@ -0,0 +1,104 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import "maps"
+
+// Transition describes a change of a probe's verdict between passing and
+// failing. It is passed to the function set with Prober.WithNotifier, and
+// is suitable for use as a JSON webhook payload.
+type Transition struct {
+	Probe string // probe name
+	Class string // probe class
+	OK    bool   // the new verdict
+
+	// External is whether the verdict was set by Probe.SetExternalVerdict
+	// rather than being the probe's latest local result.
+	External bool
+
+	// Annotations are the probe's annotations; see Probe.WithAnnotations.
+	Annotations map[string]string `json:",omitempty"`
+}
+
+// WithNotifier sets a function to be called whenever a probe's verdict
+// changes between passing and failing, including the first verdict after
+// the probe is added. By default a probe's verdict is its latest local
+// result, but it can be overridden with Probe.SetExternalVerdict.
+//
+// notify is called from probe goroutines and must be safe for concurrent
+// use. It should be set before any probes are added.
+func (p *Prober) WithNotifier(notify func(Transition)) *Prober {
+	p.notify = notify
+	return p
+}
+
+// SetExternalVerdict overrides the verdict used for notifications (see
+// Prober.WithNotifier) with ok, until ClearExternalVerdict is called.
+//
+// It's intended for probes run from multiple replicas: each replica
+// publishes its local result (e.g. via StatusHandler), and an external
+// coordinator sets the aggregate verdict, such as whether a quorum of
+// replicas agree the probe is failing. The probe's own metrics and
+// ProbeInfo.Result always reflect the local result.
+func (p *Probe) SetExternalVerdict(ok bool) {
+	p.mu.Lock()
+	p.externalVerdict = &ok
+	tr, changed := p.updateVerdictLocked()
+	p.mu.Unlock()
+	if changed {
+		p.notify(tr)
+	}
+}
+
+// ClearExternalVerdict removes a verdict set by SetExternalVerdict, so that
+// notifications are driven by the local result again.
+func (p *Probe) ClearExternalVerdict() {
+	p.mu.Lock()
+	p.externalVerdict = nil
+	tr, changed := p.updateVerdictLocked()
+	p.mu.Unlock()
+	if changed {
+		p.notify(tr)
+	}
+}
+
+// verdictLocked returns the verdict that drives notifications, and whether
+// there is one yet. p.mu must be held.
+func (p *Probe) verdictLocked() (ok, known bool) {
+	if p.externalVerdict != nil {
+		return *p.externalVerdict, true
+	}
+	if p.end.IsZero() {
+		return false, false
+	}
+	return p.succeeded, true
+}
+
+// updateVerdictLocked records the current verdict and reports whether it
+// differs from the previously notified one, along with the Transition to
+// notify if so. p.mu must be held.
+func (p *Probe) updateVerdictLocked() (tr Transition, changed bool) {
+	ok, known := p.verdictLocked()
+	if !known {
+		return Transition{}, false
+	}
+	if prev, had := p.notifiedVerdict.Get(); had && prev == ok {
+		return Transition{}, false
+	}
+	p.notifiedVerdict.Set(ok)
+	return Transition{
+		Probe:       p.name,
+		Class:       p.probeClass.Class,
+		OK:          ok,
+		External:    p.externalVerdict != nil,
+		Annotations: maps.Clone(p.annotations),
+	}, true
+}
+
+// notify calls the prober's notifier, if any, with tr.
+func (p *Probe) notify(tr Transition) {
+	if f := p.prober.notify; f != nil {
+		f(tr)
+	}
+}
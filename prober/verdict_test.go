@@ -0,0 +1,159 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package prober
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"tailscale.com/tstest"
+)
+
+func TestExternalVerdict(t *testing.T) {
+	clk := newFakeTime()
+
+	var mu sync.Mutex
+	var notes []string
+	p := newForTest(clk.Now, clk.NewTicker).WithNotifier(func(tr Transition) {
+		mu.Lock()
+		defer mu.Unlock()
+		notes = append(notes, fmt.Sprintf("%s=%v", tr.Probe, tr.OK))
+	})
+	wantNotes := func(want ...string) {
+		t.Helper()
+		err := tstest.WaitFor(convergenceTimeout, func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			if !slices.Equal(notes, want) {
+				return fmt.Errorf("got notifications %q; want %q", notes, want)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var succeed atomic.Bool
+	succeed.Store(true)
+	probe := p.Run("testprobe", probeInterval, nil, FuncProbe(func(context.Context) error {
+		if succeed.Load() {
+			return nil
+		}
+		return errors.New("failing, as instructed by test")
+	}))
+	waitActiveProbes(t, p, clk, 1)
+
+	// Without an external verdict, the local result drives notifications.
+	wantNotes("testprobe=true")
+	succeed.Store(false)
+	clk.Advance(probeInterval + halfProbeInterval)
+	wantNotes("testprobe=true", "testprobe=false")
+
+	// The external verdict overrides the local failure.
+	probe.SetExternalVerdict(true)
+	wantNotes("testprobe=true", "testprobe=false", "testprobe=true")
+
+	// Further local failures don't notify, but metrics and ProbeInfo stay
+	// local.
+	clk.Advance(probeInterval)
+	if err := tstest.WaitFor(convergenceTimeout, func() error {
+		if end := p.ProbeInfo()["testprobe"].End; !end.Equal(clk.Now()) {
+			return fmt.Errorf("last run ended at %v; want %v", end, clk.Now())
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	info := p.ProbeInfo()["testprobe"]
+	if info.Result || info.ExternalVerdict == nil || !*info.ExternalVerdict {
+		t.Errorf("ProbeInfo: Result=%v ExternalVerdict=%v; want false, true", info.Result, info.ExternalVerdict)
+	}
+	wantNotes("testprobe=true", "testprobe=false", "testprobe=true")
+
+	// Repeating the same external verdict doesn't notify again.
+	probe.SetExternalVerdict(true)
+	wantNotes("testprobe=true", "testprobe=false", "testprobe=true")
+	probe.SetExternalVerdict(false)
+	wantNotes("testprobe=true", "testprobe=false", "testprobe=true", "testprobe=false")
+
+	// Clearing the external verdict reverts to the local result, which
+	// matches the last notified verdict.
+	succeed.Store(true)
+	probe.ClearExternalVerdict()
+	wantNotes("testprobe=true", "testprobe=false", "testprobe=true", "testprobe=false")
+	clk.Advance(probeInterval)
+	wantNotes("testprobe=true", "testprobe=false", "testprobe=true", "testprobe=false", "testprobe=true")
+	if info := p.ProbeInfo()["testprobe"]; info.ExternalVerdict != nil {
+		t.Errorf("ProbeInfo.ExternalVerdict = %v after clearing; want nil", *info.ExternalVerdict)
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	clk := newFakeTime()
+	transitions := make(chan Transition, 1)
+	p := newForTest(clk.Now, clk.NewTicker).WithOnce(true).WithNotifier(func(tr Transition) {
+		transitions <- tr
+	})
+
+	release := make(chan struct{})
+	annotations := map[string]string{
+		"runbook": "https://example.com/runbooks/testprobe",
+		"summary": "checks that the test passes",
+	}
+	p.Run("testprobe", probeInterval, Labels{"region": "test"}, FuncProbe(func(context.Context) error {
+		<-release
+		return errors.New("failing, as instructed by test")
+	})).WithAnnotations(annotations)
+	close(release)
+	p.Wait()
+
+	// Notification payload.
+	tr := <-transitions
+	if tr.Probe != "testprobe" || tr.OK || tr.External {
+		t.Errorf("got transition %+v; want local failure of testprobe", tr)
+	}
+	if !maps.Equal(tr.Annotations, annotations) {
+		t.Errorf("transition annotations = %v; want %v", tr.Annotations, annotations)
+	}
+
+	// Status handler JSON.
+	rec := httptest.NewRecorder()
+	p.StatusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/status", nil))
+	var status map[string]ProbeInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if got := status["testprobe"].Annotations; !maps.Equal(got, annotations) {
+		t.Errorf("status annotations = %v; want %v", got, annotations)
+	}
+
+	// Not in metrics.
+	mfs, err := p.metrics.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sawRegion bool
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if _, ok := annotations[lp.GetName()]; ok {
+					t.Errorf("metric %s has annotation label %q", mf.GetName(), lp.GetName())
+				}
+				sawRegion = sawRegion || lp.GetName() == "region"
+			}
+		}
+	}
+	if !sawRegion {
+		t.Error("no metrics with the region label; test is broken")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package views
+
+import "net/netip"
+
+// AddrSlice is a read-only view over a slice of IP addresses, with helpers
+// for common address operations.
+type AddrSlice struct {
+	Slice[netip.Addr]
+}
+
+// AddrSliceOf returns an AddrSlice view over x.
+func AddrSliceOf(x []netip.Addr) AddrSlice {
+	return AddrSlice{SliceOf(x)}
+}
+
+// ContainsAddr reports whether v contains a.
+//
+// As it runs in O(n) time, use with care.
+func (v AddrSlice) ContainsAddr(a netip.Addr) bool {
+	return SliceContains(v.Slice, a)
+}
+
+// FilterByPrefix returns a view over a new slice containing the addresses
+// in v that are within p, in their original order.
+func (v AddrSlice) FilterByPrefix(p netip.Prefix) Slice[netip.Addr] {
+	var out []netip.Addr
+	for _, a := range v.ж {
+		if p.Contains(a) {
+			out = append(out, a)
+		}
+	}
+	return SliceOf(out)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package views
+
+import (
+	"encoding/json"
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func TestAddrSlice(t *testing.T) {
+	addr := netip.MustParseAddr
+	v := AddrSliceOf([]netip.Addr{
+		addr("100.64.0.1"),
+		addr("fd7a:115c:a1e0::1"),
+		addr("192.168.1.10"),
+		addr("100.100.100.100"),
+		addr("fd7a:115c:a1e0::2"),
+		addr("2001:db8::1"),
+	})
+
+	if !v.ContainsAddr(addr("192.168.1.10")) {
+		t.Error("ContainsAddr(192.168.1.10) = false; want true")
+	}
+	if v.ContainsAddr(addr("192.168.1.11")) {
+		t.Error("ContainsAddr(192.168.1.11) = true; want false")
+	}
+	if v.ContainsAddr(addr("::ffff:192.168.1.10")) {
+		t.Error("ContainsAddr(::ffff:192.168.1.10) = true; want false")
+	}
+
+	tests := []struct {
+		prefix string
+		want   []netip.Addr
+	}{
+		{"100.64.0.0/10", []netip.Addr{addr("100.64.0.1"), addr("100.100.100.100")}},
+		{"fd7a:115c:a1e0::/48", []netip.Addr{addr("fd7a:115c:a1e0::1"), addr("fd7a:115c:a1e0::2")}},
+		{"0.0.0.0/0", []netip.Addr{addr("100.64.0.1"), addr("192.168.1.10"), addr("100.100.100.100")}},
+		{"::/0", []netip.Addr{addr("fd7a:115c:a1e0::1"), addr("fd7a:115c:a1e0::2"), addr("2001:db8::1")}},
+		{"10.0.0.0/8", nil},
+	}
+	for _, tt := range tests {
+		got := v.FilterByPrefix(netip.MustParsePrefix(tt.prefix))
+		if !slices.Equal(got.AsSlice(), tt.want) {
+			t.Errorf("FilterByPrefix(%s) = %v; want %v", tt.prefix, got.AsSlice(), tt.want)
+		}
+	}
+
+	// AddrSlice has the JSON representation of the underlying Slice.
+	j, err := json.Marshal(AddrSliceOf([]netip.Addr{addr("100.64.0.1"), addr("::1")}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(j), `["100.64.0.1","::1"]`; got != want {
+		t.Errorf("JSON = %s; want %s", got, want)
+	}
+}
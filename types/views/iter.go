@@ -0,0 +1,273 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build go1.23
+
+package views
+
+import (
+	"context"
+	"iter"
+	"net/netip"
+	"slices"
+)
+
+// SliceAllCtx returns an iterator over the index-value pairs in v that stops
+// yielding once ctx is done. The context is checked before each element.
+func SliceAllCtx[T any](ctx context.Context, v Slice[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, x := range v.ж {
+			if ctx.Err() != nil {
+				return
+			}
+			if !yield(i, x) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the index-value pairs in v, in order.
+// It does not copy v.
+func (v Slice[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, x := range v.ж {
+			if !yield(i, x) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values in v, in order.
+// It does not copy v.
+func (v Slice[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, x := range v.ж {
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the index-value pairs in v, traversing
+// it backward with descending indices. It does not copy v.
+func (v Slice[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := len(v.ж) - 1; i >= 0; i-- {
+			if !yield(i, v.ж[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns an iterator over consecutive sub-views of v of length n.
+// The last sub-view may be shorter than n. The sub-views share v's backing
+// array. Chunk panics if n is less than 1.
+func (v Slice[T]) Chunk(n int) iter.Seq[Slice[T]] {
+	if n < 1 {
+		panic("cannot be less than 1")
+	}
+	return func(yield func(Slice[T]) bool) {
+		for i := 0; i < len(v.ж); i += n {
+			end := min(i+n, len(v.ж))
+			if !yield(v.Slice(i, end)) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the index-view pairs in v, in order. Each
+// element's view is created only when it is yielded, so a consumer that
+// stops early doesn't pay for views it never reads.
+func (v SliceView[T, V]) All() iter.Seq2[int, V] {
+	return func(yield func(int, V) bool) {
+		for i, x := range v.ж {
+			if !yield(i, x.View()) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the index-view pairs in v, traversing
+// it backward with descending indices.
+func (v SliceView[T, V]) Backward() iter.Seq2[int, V] {
+	return func(yield func(int, V) bool) {
+		for i := len(v.ж) - 1; i >= 0; i-- {
+			if !yield(i, v.ж[i].View()) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over the addresses in v in ascending order, as
+// defined by netip.Addr.Compare (IPv4 before IPv6). It sorts a copy of v,
+// leaving v itself unchanged.
+func (v AddrSlice) Sorted() iter.Seq[netip.Addr] {
+	return func(yield func(netip.Addr) bool) {
+		sorted := slices.Clone(v.ж)
+		slices.SortFunc(sorted, netip.Addr.Compare)
+		for _, a := range sorted {
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
+
+// SliceBatches returns an iterator that partitions v into at most workers
+// contiguous sub-views of roughly equal length, yielding each with its
+// worker index. The sub-views share v's backing array. Batch lengths differ
+// by at most one, with the longer batches first. If workers exceeds
+// v.Len(), fewer batches are yielded so that none is empty; if workers is
+// less than one, it is treated as one.
+func SliceBatches[T any](v Slice[T], workers int) iter.Seq2[int, Slice[T]] {
+	return func(yield func(int, Slice[T]) bool) {
+		n := v.Len()
+		workers = min(max(workers, 1), n)
+		if workers == 0 {
+			return
+		}
+		size, rem := n/workers, n%workers
+		start := 0
+		for i := range workers {
+			end := start + size
+			if i < rem {
+				end++
+			}
+			if !yield(i, v.Slice(start, end)) {
+				return
+			}
+			start = end
+		}
+	}
+}
+
+// SliceZip returns an iterator over corresponding pairs of elements of a and
+// b. It stops at the end of the shorter of the two.
+func SliceZip[A, B any](a Slice[A], b Slice[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		n := min(a.Len(), b.Len())
+		for i := range n {
+			if !yield(a.ж[i], b.ж[i]) {
+				return
+			}
+		}
+	}
+}
+
+// ZipValue is an element yielded by SliceZipLongest. OK reports whether
+// Value came from the view; if false, the view was exhausted and Value is
+// the zero value.
+type ZipValue[T any] struct {
+	Value T
+	OK    bool
+}
+
+// SliceZipLongest is like SliceZip, but continues to the end of the longer
+// of a and b, padding the shorter one with zero values whose OK field is
+// false.
+func SliceZipLongest[A, B any](a Slice[A], b Slice[B]) iter.Seq2[ZipValue[A], ZipValue[B]] {
+	return func(yield func(ZipValue[A], ZipValue[B]) bool) {
+		n := max(a.Len(), b.Len())
+		for i := range n {
+			var za ZipValue[A]
+			var zb ZipValue[B]
+			if i < a.Len() {
+				za = ZipValue[A]{a.ж[i], true}
+			}
+			if i < b.Len() {
+				zb = ZipValue[B]{b.ж[i], true}
+			}
+			if !yield(za, zb) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the elements of the stack, from top to
+// bottom.
+func (s Stack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := s.v.Len() - 1; i >= 0; i-- {
+			if !yield(s.v.ж[i]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the elements of the queue, from front to
+// back.
+func (q Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, x := range q.v.ж {
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the key-value pairs in m, in unspecified
+// order.
+func (m Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m.ж {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys in m, in unspecified order.
+func (m Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.ж {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values in m, in unspecified order.
+func (m Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.ж {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the elements of the set, in unspecified
+// order.
+func (v SetView[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := range v.ж {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys in m, in unspecified order.
+func (m MapFn[K, T, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.ж {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,512 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build go1.23
+
+package views
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSliceAllCtx(t *testing.T) {
+	v := SliceOf([]int{0, 1, 2, 3, 4, 5})
+
+	var got []int
+	for i, x := range SliceAllCtx(context.Background(), v) {
+		if i != x {
+			t.Errorf("index %d has value %d", i, x)
+		}
+		got = append(got, x)
+	}
+	if len(got) != v.Len() {
+		t.Errorf("got %d elements; want %d", len(got), v.Len())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got = nil
+	for _, x := range SliceAllCtx(ctx, v) {
+		got = append(got, x)
+		if x == 2 {
+			cancel()
+		}
+	}
+	if len(got) != 3 {
+		t.Errorf("after cancel: got %v; want [0 1 2]", got)
+	}
+
+	got = nil
+	for _, x := range SliceAllCtx(ctx, v) {
+		got = append(got, x)
+	}
+	if got != nil {
+		t.Errorf("canceled context: got %v; want nothing", got)
+	}
+}
+
+func TestStackQueue(t *testing.T) {
+	v := SliceOf([]int{1, 2, 3})
+
+	st := StackOf(v)
+	if got, ok := st.Peek(); !ok || got != 3 {
+		t.Errorf("Stack.Peek = %v, %v; want 3, true", got, ok)
+	}
+	if got := slices.Collect(st.All()); !slices.Equal(got, []int{3, 2, 1}) {
+		t.Errorf("Stack.All = %v; want [3 2 1]", got)
+	}
+
+	q := QueueOf(v)
+	if got, ok := q.Peek(); !ok || got != 1 {
+		t.Errorf("Queue.Peek = %v, %v; want 1, true", got, ok)
+	}
+	if got := slices.Collect(q.All()); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Queue.All = %v; want [1 2 3]", got)
+	}
+	if st.Len() != 3 || q.Len() != 3 {
+		t.Errorf("Len = %d, %d; want 3, 3", st.Len(), q.Len())
+	}
+
+	for x := range st.All() {
+		if x != 3 {
+			t.Errorf("Stack.All: first element %d; want 3", x)
+		}
+		break
+	}
+
+	empty := SliceOf[int](nil)
+	if _, ok := StackOf(empty).Peek(); ok {
+		t.Error("empty Stack.Peek: got ok")
+	}
+	if _, ok := QueueOf(empty).Peek(); ok {
+		t.Error("empty Queue.Peek: got ok")
+	}
+}
+
+func TestSliceBatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []int
+		workers int
+		want    [][]int
+	}{
+		{"even", []int{1, 2, 3, 4, 5, 6}, 3, [][]int{{1, 2}, {3, 4}, {5, 6}}},
+		{"uneven", []int{1, 2, 3, 4, 5, 6, 7}, 3, [][]int{{1, 2, 3}, {4, 5}, {6, 7}}},
+		{"more_workers", []int{1, 2}, 5, [][]int{{1}, {2}}},
+		{"one_worker", []int{1, 2, 3}, 1, [][]int{{1, 2, 3}}},
+		{"zero_workers", []int{1, 2, 3}, 0, [][]int{{1, 2, 3}}},
+		{"empty", nil, 4, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]int
+			for i, b := range SliceBatches(SliceOf(tt.in), tt.workers) {
+				if i != len(got) {
+					t.Errorf("worker index %d; want %d", i, len(got))
+				}
+				got = append(got, b.AsSlice())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Batches must be zero-copy sub-views of the input.
+	in := []int{1, 2, 3, 4}
+	for _, b := range SliceBatches(SliceOf(in), 2) {
+		if &b.ж[0] != &in[0] {
+			t.Error("first batch does not alias input")
+		}
+		break
+	}
+}
+
+func TestSliceZip(t *testing.T) {
+	type pair struct {
+		a int
+		b string
+	}
+	collect := func(a []int, b []string) []pair {
+		var out []pair
+		for x, y := range SliceZip(SliceOf(a), SliceOf(b)) {
+			out = append(out, pair{x, y})
+		}
+		return out
+	}
+	if got, want := collect([]int{1, 2}, []string{"a", "b"}), []pair{{1, "a"}, {2, "b"}}; !slices.Equal(got, want) {
+		t.Errorf("equal lengths: got %v; want %v", got, want)
+	}
+	if got, want := collect([]int{1, 2, 3}, []string{"a"}), []pair{{1, "a"}}; !slices.Equal(got, want) {
+		t.Errorf("longer a: got %v; want %v", got, want)
+	}
+	if got, want := collect([]int{1}, []string{"a", "b"}), []pair{{1, "a"}}; !slices.Equal(got, want) {
+		t.Errorf("longer b: got %v; want %v", got, want)
+	}
+	if got := collect(nil, []string{"a"}); got != nil {
+		t.Errorf("empty a: got %v; want nil", got)
+	}
+}
+
+func TestSliceZipLongest(t *testing.T) {
+	type pair struct {
+		a ZipValue[int]
+		b ZipValue[string]
+	}
+	collect := func(a []int, b []string) []pair {
+		var out []pair
+		for x, y := range SliceZipLongest(SliceOf(a), SliceOf(b)) {
+			out = append(out, pair{x, y})
+		}
+		return out
+	}
+	got := collect([]int{1, 2}, []string{"a", "b"})
+	want := []pair{
+		{ZipValue[int]{1, true}, ZipValue[string]{"a", true}},
+		{ZipValue[int]{2, true}, ZipValue[string]{"b", true}},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("equal lengths: got %v; want %v", got, want)
+	}
+	got = collect([]int{1, 2, 3}, []string{"a"})
+	want = []pair{
+		{ZipValue[int]{1, true}, ZipValue[string]{"a", true}},
+		{ZipValue[int]{2, true}, ZipValue[string]{}},
+		{ZipValue[int]{3, true}, ZipValue[string]{}},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("longer a: got %v; want %v", got, want)
+	}
+	got = collect(nil, []string{"a", "b"})
+	want = []pair{
+		{ZipValue[int]{}, ZipValue[string]{"a", true}},
+		{ZipValue[int]{}, ZipValue[string]{"b", true}},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("empty a: got %v; want %v", got, want)
+	}
+
+	var n int
+	for range SliceZipLongest(SliceOf([]int{1, 2, 3}), SliceOf[string](nil)) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("early break: got %d iterations; want 1", n)
+	}
+}
+
+func TestSliceAll(t *testing.T) {
+	s := []int{10, 11, 12, 13}
+	v := SliceOf(s)
+
+	var idx, got []int
+	for i, x := range v.All() {
+		idx = append(idx, i)
+		got = append(got, x)
+	}
+	if !slices.Equal(idx, []int{0, 1, 2, 3}) || !slices.Equal(got, s) {
+		t.Errorf("All = %v, %v; want [0 1 2 3], %v", idx, got, s)
+	}
+	got = nil
+	for x := range v.Values() {
+		got = append(got, x)
+	}
+	if !slices.Equal(got, s) {
+		t.Errorf("Values = %v; want %v", got, s)
+	}
+
+	// Breaking out of the loop stops the iteration.
+	got = nil
+	for i, x := range v.All() {
+		got = append(got, x)
+		if i == 1 {
+			break
+		}
+	}
+	if !slices.Equal(got, []int{10, 11}) {
+		t.Errorf("All with break = %v; want [10 11]", got)
+	}
+	got = nil
+	for x := range v.Values() {
+		got = append(got, x)
+		break
+	}
+	if !slices.Equal(got, []int{10}) {
+		t.Errorf("Values with break = %v; want [10]", got)
+	}
+
+	// The iterators read the backing array directly rather than a copy.
+	got = nil
+	for i, x := range v.All() {
+		if i == 0 {
+			s[3] = 99
+		}
+		got = append(got, x)
+	}
+	s[3] = 13
+	if !slices.Equal(got, []int{10, 11, 12, 99}) {
+		t.Errorf("All after modifying backing array = %v; want [10 11 12 99]", got)
+	}
+	var sum int
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, x := range v.All() {
+			sum += x
+		}
+		for x := range v.Values() {
+			sum += x
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("iterating allocated %v times; want 0", allocs)
+	}
+}
+
+func TestSliceViewAll(t *testing.T) {
+	sv := SliceOfViews([]*testStruct{{1}, {2}, {3}})
+	var idx, got []int
+	for i, x := range sv.All() {
+		idx = append(idx, i)
+		got = append(got, x.AsStruct().N)
+	}
+	if !slices.Equal(idx, []int{0, 1, 2}) || !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("All = %v, %v; want [0 1 2], [1 2 3]", idx, got)
+	}
+
+	got = nil
+	for _, x := range sv.All() {
+		got = append(got, x.AsStruct().N)
+		break
+	}
+	if !slices.Equal(got, []int{1}) {
+		t.Errorf("All with break = %v; want [1]", got)
+	}
+}
+
+func BenchmarkSliceViewIteration(b *testing.B) {
+	data := make([]*testStruct, 1000)
+	for i := range data {
+		data[i] = &testStruct{i}
+	}
+	sv := SliceOfViews(data)
+	b.Run("All", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			sum := 0
+			for _, x := range sv.All() {
+				sum += x.ж.N
+			}
+		}
+	})
+	b.Run("AsSlice", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			sum := 0
+			for _, x := range sv.AsSlice() {
+				sum += x.ж.N
+			}
+		}
+	})
+}
+
+func TestBackward(t *testing.T) {
+	v := SliceOf([]string{"a", "b", "c"})
+	var idx []int
+	var got []string
+	for i, x := range v.Backward() {
+		idx = append(idx, i)
+		got = append(got, x)
+	}
+	if !slices.Equal(idx, []int{2, 1, 0}) || !slices.Equal(got, []string{"c", "b", "a"}) {
+		t.Errorf("Slice.Backward = %v, %q; want [2 1 0], [c b a]", idx, got)
+	}
+	for i, x := range v.Backward() {
+		if i != 2 || x != "c" {
+			t.Errorf("first element = %d, %q; want 2, c", i, x)
+		}
+		break
+	}
+	for range SliceOf[int](nil).Backward() {
+		t.Error("empty Slice.Backward yielded an element")
+	}
+
+	src := []int{5, 1, 4, 2, 3}
+	want := slices.Clone(src)
+	slices.Reverse(want)
+	var gotInts []int
+	for _, x := range SliceOf(src).Backward() {
+		gotInts = append(gotInts, x)
+	}
+	if !slices.Equal(gotInts, want) {
+		t.Errorf("Slice.Backward = %v; want %v", gotInts, want)
+	}
+	if !slices.Equal(src, []int{5, 1, 4, 2, 3}) {
+		t.Errorf("Slice.Backward modified its backing slice: %v", src)
+	}
+
+	sv := SliceOfViews([]*testStruct{{1}, {2}, {3}})
+	idx, got = nil, nil
+	for i, x := range sv.Backward() {
+		idx = append(idx, i)
+		got = append(got, fmt.Sprint(x.AsStruct().N))
+	}
+	if !slices.Equal(idx, []int{2, 1, 0}) || !slices.Equal(got, []string{"3", "2", "1"}) {
+		t.Errorf("SliceView.Backward = %v, %q; want [2 1 0], [3 2 1]", idx, got)
+	}
+}
+
+func TestAddrSliceSorted(t *testing.T) {
+	in := []netip.Addr{
+		netip.MustParseAddr("fd7a:115c:a1e0::1"),
+		netip.MustParseAddr("100.64.0.2"),
+		netip.MustParseAddr("10.0.0.1"),
+		netip.MustParseAddr("::1"),
+		netip.MustParseAddr("100.64.0.1"),
+	}
+	orig := slices.Clone(in)
+	got := slices.Collect(AddrSliceOf(in).Sorted())
+	want := []netip.Addr{
+		netip.MustParseAddr("10.0.0.1"),
+		netip.MustParseAddr("100.64.0.1"),
+		netip.MustParseAddr("100.64.0.2"),
+		netip.MustParseAddr("::1"),
+		netip.MustParseAddr("fd7a:115c:a1e0::1"),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Sorted = %v; want %v", got, want)
+	}
+	if !slices.Equal(in, orig) {
+		t.Errorf("Sorted modified its input: %v", in)
+	}
+}
+
+func TestMapIterators(t *testing.T) {
+	src := map[string]int{"a": 1, "b": 2, "c": 3}
+	m := MapOf(src)
+
+	seen := map[string]int{}
+	for k, v := range m.All() {
+		if v != src[k] {
+			t.Errorf("All yielded %q=%d; want %d", k, v, src[k])
+		}
+		seen[k]++
+	}
+	for k := range m.Keys() {
+		seen[k]++
+	}
+	for k, n := range seen {
+		if n != 2 {
+			t.Errorf("key %q visited %d times by All and Keys; want once each", k, n)
+		}
+	}
+	if len(seen) != len(src) {
+		t.Errorf("visited %d keys; want %d", len(seen), len(src))
+	}
+	var vals []int
+	for v := range m.Values() {
+		vals = append(vals, v)
+	}
+	slices.Sort(vals)
+	if !slices.Equal(vals, []int{1, 2, 3}) {
+		t.Errorf("Values = %v; want [1 2 3]", vals)
+	}
+
+	// Breaking out of the loop stops the iteration.
+	var n int
+	for range m.All() {
+		n++
+		break
+	}
+	for range m.Keys() {
+		n++
+		break
+	}
+	for range m.Values() {
+		n++
+		break
+	}
+	if n != 3 {
+		t.Errorf("got %d iterations with break; want 3", n)
+	}
+
+	for range MapOf[string, int](nil).All() {
+		t.Error("nil Map.All yielded an element")
+	}
+}
+
+func TestSetViewAll(t *testing.T) {
+	s := SetOf(map[int]struct{}{1: {}, 2: {}, 3: {}})
+	var got []int
+	for e := range s.All() {
+		got = append(got, e)
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("All = %v; want [1 2 3]", got)
+	}
+	var n int
+	for range s.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("All with break yielded %d elements; want 1", n)
+	}
+}
+
+func TestMapFnKeys(t *testing.T) {
+	m := MapFnOf(map[string][]int{"b": {2}, "a": {1}}, SliceOf[int])
+	got := slices.Sorted(m.Keys())
+	if !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Keys = %q; want [a b]", got)
+	}
+	for range MapFnOf[string, []int](nil, SliceOf[int]).Keys() {
+		t.Error("nil MapFn.Keys yielded a key")
+	}
+}
+
+func TestSliceChunk(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		n    int
+		want [][]int
+	}{
+		{"exact", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"remainder", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"larger_than_len", []int{1, 2}, 5, [][]int{{1, 2}}},
+		{"empty", nil, 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]int
+			for c := range SliceOf(tt.in).Chunk(tt.n) {
+				got = append(got, c.AsSlice())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Chunks must be zero-copy sub-views of the input.
+	in := []int{1, 2, 3, 4}
+	for c := range SliceOf(in).Chunk(3) {
+		if &c.ж[0] != &in[0] {
+			t.Error("first chunk does not alias input")
+		}
+		break
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Chunk(0) did not panic")
+		}
+	}()
+	SliceOf(in).Chunk(0)
+}
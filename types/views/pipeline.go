@@ -0,0 +1,120 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build go1.23
+
+package views
+
+import "iter"
+
+// Pipeline is a lazy chain of transformations over the elements of a Slice.
+// No element is examined until a terminal method (Collect, ForEach, or
+// ranging over All) is called.
+//
+// The zero value is an empty pipeline.
+type Pipeline[T any] struct {
+	seq iter.Seq[T]
+}
+
+// Pipe returns a Pipeline over the elements of v.
+func Pipe[T any](v Slice[T]) Pipeline[T] {
+	return Pipeline[T]{v.Values()}
+}
+
+// Filter returns a pipeline that yields only the elements of p for which
+// keep returns true.
+func (p Pipeline[T]) Filter(keep func(T) bool) Pipeline[T] {
+	return Pipeline[T]{func(yield func(T) bool) {
+		for x := range p.All() {
+			if keep(x) && !yield(x) {
+				return
+			}
+		}
+	}}
+}
+
+// Map returns a pipeline that yields f applied to each element of p.
+func (p Pipeline[T]) Map(f func(T) T) Pipeline[T] {
+	return Pipeline[T]{func(yield func(T) bool) {
+		for x := range p.All() {
+			if !yield(f(x)) {
+				return
+			}
+		}
+	}}
+}
+
+// All returns an iterator over the elements produced by p.
+func (p Pipeline[T]) All() iter.Seq[T] {
+	if p.seq == nil {
+		return func(func(T) bool) {}
+	}
+	return p.seq
+}
+
+// Collect runs p and returns a view of the elements it produced. It returns
+// a nil view if p produced no elements.
+func (p Pipeline[T]) Collect() Slice[T] {
+	var out []T
+	for x := range p.All() {
+		out = append(out, x)
+	}
+	return SliceOf(out)
+}
+
+// ForEach runs p, calling f with each element it produces.
+func (p Pipeline[T]) ForEach(f func(T)) {
+	for x := range p.All() {
+		f(x)
+	}
+}
+
+// FilterView is a lazily filtered view of a Slice. The predicate is
+// evaluated during iteration, so chaining filters with Filter doesn't
+// allocate intermediate slices.
+//
+// FilterView has no Len method: the number of kept elements can only be
+// known by evaluating the predicate over every element, which is O(n).
+type FilterView[T any] struct {
+	v    Slice[T]
+	keep func(T) bool
+}
+
+// FilterOf returns a view of the elements of v for which keep returns true.
+func FilterOf[T any](v Slice[T], keep func(T) bool) FilterView[T] {
+	return FilterView[T]{v, keep}
+}
+
+// Filter returns a view of the elements of f for which keep also returns
+// true.
+func (f FilterView[T]) Filter(keep func(T) bool) FilterView[T] {
+	prev := f.keep
+	return FilterView[T]{f.v, func(x T) bool { return prev(x) && keep(x) }}
+}
+
+// All returns an iterator over the kept elements, in order.
+func (f FilterView[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, x := range f.v.ж {
+			if f.keep(x) && !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// AppendTo appends the kept elements to dst and returns it.
+func (f FilterView[T]) AppendTo(dst []T) []T {
+	for _, x := range f.v.ж {
+		if f.keep(x) {
+			dst = append(dst, x)
+		}
+	}
+	return dst
+}
+
+// AsSlice returns a new slice of the kept elements, or nil if none are
+// kept.
+func (f FilterView[T]) AsSlice() []T {
+	return f.AppendTo(nil)
+}
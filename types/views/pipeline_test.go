@@ -0,0 +1,88 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+//go:build go1.23
+
+package views
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	var filtered, mapped int
+	p := Pipe(SliceOf([]int{1, 2, 3, 4, 5, 6})).
+		Filter(func(x int) bool {
+			filtered++
+			return x%2 == 0
+		}).
+		Map(func(x int) int {
+			mapped++
+			return x * 10
+		})
+	if filtered != 0 || mapped != 0 {
+		t.Fatalf("pipeline did work before a terminal call: filtered=%d, mapped=%d", filtered, mapped)
+	}
+
+	if got, want := p.Collect().AsSlice(), []int{20, 40, 60}; !slices.Equal(got, want) {
+		t.Errorf("Collect = %v; want %v", got, want)
+	}
+	if filtered != 6 || mapped != 3 {
+		t.Errorf("filtered=%d, mapped=%d; want 6, 3", filtered, mapped)
+	}
+
+	var got []int
+	p.ForEach(func(x int) { got = append(got, x) })
+	if want := []int{20, 40, 60}; !slices.Equal(got, want) {
+		t.Errorf("ForEach = %v; want %v", got, want)
+	}
+
+	// Stopping early must not run the rest of the pipeline.
+	filtered, mapped = 0, 0
+	for range p.All() {
+		break
+	}
+	if filtered != 2 || mapped != 1 {
+		t.Errorf("after break: filtered=%d, mapped=%d; want 2, 1", filtered, mapped)
+	}
+
+	if got := Pipe(SliceOf([]int{1, 3})).Filter(func(x int) bool { return x%2 == 0 }).Collect(); !got.IsNil() {
+		t.Errorf("Collect of empty result = %v; want nil", got)
+	}
+	var zero Pipeline[int]
+	if got := zero.Collect(); got.Len() != 0 {
+		t.Errorf("zero Pipeline Collect = %v; want empty", got)
+	}
+}
+
+func TestFilterView(t *testing.T) {
+	even := func(x int) bool { return x%2 == 0 }
+	big := func(x int) bool { return x > 2 }
+	var calls int
+	counted := func(x int) bool {
+		calls++
+		return even(x)
+	}
+
+	f := FilterOf(SliceOf([]int{1, 2, 3, 4, 5, 6}), counted).Filter(big)
+	if calls != 0 {
+		t.Fatalf("predicate called %d times before iteration; want 0", calls)
+	}
+	want := []int{4, 6}
+	if got := f.AsSlice(); !slices.Equal(got, want) {
+		t.Errorf("AsSlice = %v; want %v", got, want)
+	}
+	if got := slices.Collect(f.All()); !slices.Equal(got, want) {
+		t.Errorf("All = %v; want %v", got, want)
+	}
+
+	buf := make([]int, 0, 6)
+	if n := testing.AllocsPerRun(100, func() { buf = f.AppendTo(buf[:0]) }); n != 0 {
+		t.Errorf("AppendTo through two filters allocs = %v; want 0", n)
+	}
+
+	if got := FilterOf(SliceOf([]int{1, 3}), even).AsSlice(); got != nil {
+		t.Errorf("AsSlice with nothing kept = %#v; want nil", got)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package views
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// SetView is a read-only view of a set represented as a map[T]struct{}.
+type SetView[T comparable] struct {
+	// ж is the underlying mutable value, named with a hard-to-type
+	// character that looks pointy like a pointer.
+	// It is named distinctively to make you think of how dangerous it is to escape
+	// to callers. You must not let callers be able to mutate it.
+	ж map[T]struct{}
+}
+
+// SetOf returns a view over m. It is the caller's responsibility to make
+// sure T is immutable.
+func SetOf[T comparable, S ~map[T]struct{}](m S) SetView[T] {
+	return SetView[T]{m}
+}
+
+// Contains reports whether e is in the set.
+func (v SetView[T]) Contains(e T) bool {
+	_, ok := v.ж[e]
+	return ok
+}
+
+// Len returns the number of elements in the set.
+func (v SetView[T]) Len() int { return len(v.ж) }
+
+// IsNil reports whether the underlying map is nil.
+func (v SetView[T]) IsNil() bool { return v.ж == nil }
+
+// AsSlice returns the elements of the set in a new slice, in unspecified
+// order. It returns nil if the set is nil.
+func (v SetView[T]) AsSlice() []T {
+	if v.ж == nil {
+		return nil
+	}
+	s := make([]T, 0, len(v.ж))
+	for e := range v.ж {
+		s = append(s, e)
+	}
+	return s
+}
+
+// MarshalJSON implements json.Marshaler. The set is encoded as a JSON array,
+// or null if the set is nil. To keep the output stable, the elements are
+// sorted by their JSON encoding.
+func (v SetView[T]) MarshalJSON() ([]byte, error) {
+	if v.ж == nil {
+		return []byte("null"), nil
+	}
+	elems := make([][]byte, 0, len(v.ж))
+	for e := range v.ж {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, b)
+	}
+	slices.SortFunc(elems, bytes.Compare)
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	buf.Write(bytes.Join(elems, []byte(",")))
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It decodes a JSON array, which
+// may contain duplicates, into the set.
+func (v *SetView[T]) UnmarshalJSON(b []byte) error {
+	if v.ж != nil {
+		return errors.New("already initialized")
+	}
+	var s []T
+	if err := unmarshalSliceFromJSON(b, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+	v.ж = make(map[T]struct{}, len(s))
+	for _, e := range s {
+		v.ж[e] = struct{}{}
+	}
+	return nil
+}
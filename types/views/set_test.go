@@ -0,0 +1,81 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package views
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestSetView(t *testing.T) {
+	s := SetOf(map[string]struct{}{"b": {}, "a": {}, "c": {}})
+	if s.Len() != 3 || s.IsNil() {
+		t.Errorf("Len = %d, IsNil = %v; want 3, false", s.Len(), s.IsNil())
+	}
+	if !s.Contains("a") || s.Contains("d") {
+		t.Errorf("Contains(a) = %v, Contains(d) = %v; want true, false", s.Contains("a"), s.Contains("d"))
+	}
+	got := s.AsSlice()
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("AsSlice = %q; want [a b c]", got)
+	}
+
+	var nilSet SetView[string]
+	if !nilSet.IsNil() || nilSet.Len() != 0 || nilSet.AsSlice() != nil {
+		t.Errorf("nil set: IsNil = %v, Len = %d, AsSlice = %v", nilSet.IsNil(), nilSet.Len(), nilSet.AsSlice())
+	}
+	empty := SetOf(map[string]struct{}{})
+	if empty.IsNil() || empty.Len() != 0 || empty.AsSlice() == nil {
+		t.Errorf("empty set: IsNil = %v, Len = %d, AsSlice = %v", empty.IsNil(), empty.Len(), empty.AsSlice())
+	}
+}
+
+func TestSetViewJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   SetView[int]
+		want string
+	}{
+		{"nil", SetView[int]{}, "null"},
+		{"empty", SetOf(map[int]struct{}{}), "[]"},
+		{"elems", SetOf(map[int]struct{}{3: {}, 1: {}, 2: {}}), "[1,2,3]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("Marshal = %s; want %s", b, tt.want)
+			}
+
+			var back SetView[int]
+			if err := json.Unmarshal(b, &back); err != nil {
+				t.Fatal(err)
+			}
+			if back.IsNil() != tt.in.IsNil() || back.Len() != tt.in.Len() {
+				t.Errorf("round trip: IsNil = %v, Len = %d; want %v, %d", back.IsNil(), back.Len(), tt.in.IsNil(), tt.in.Len())
+			}
+			for e := range tt.in.ж {
+				if !back.Contains(e) {
+					t.Errorf("round trip lost element %d", e)
+				}
+			}
+		})
+	}
+
+	var dup SetView[int]
+	if err := json.Unmarshal([]byte("[1,1,2]"), &dup); err != nil {
+		t.Fatal(err)
+	}
+	if dup.Len() != 2 {
+		t.Errorf("duplicates: Len = %d; want 2", dup.Len())
+	}
+	if err := json.Unmarshal([]byte("[3]"), &dup); err == nil {
+		t.Error("unmarshaling into initialized set succeeded")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package views
+
+import (
+	"slices"
+	"time"
+)
+
+// TimeSlice is a read-only view over a slice of times, with helpers for
+// selecting time windows.
+type TimeSlice struct {
+	Slice[time.Time]
+}
+
+// TimeSliceOf returns a TimeSlice view over x.
+func TimeSliceOf(x []time.Time) TimeSlice {
+	return TimeSlice{SliceOf(x)}
+}
+
+// Within returns a view of the times t in v with start <= t < end, sharing
+// v's underlying array.
+//
+// It uses binary search and so assumes v is sorted in ascending order; if
+// it is not, the result is unspecified. Use WithinUnsorted for unsorted
+// slices.
+func (v TimeSlice) Within(start, end time.Time) Slice[time.Time] {
+	if !start.Before(end) {
+		return Slice[time.Time]{}
+	}
+	i, _ := slices.BinarySearchFunc(v.ж, start, time.Time.Compare)
+	j, _ := slices.BinarySearchFunc(v.ж[i:], end, time.Time.Compare)
+	return v.Slice.Slice(i, i+j)
+}
+
+// WithinUnsorted is like Within, but does not assume v is sorted. It runs
+// in linear time and returns a view over a new slice holding the matching
+// times in their original order.
+func (v TimeSlice) WithinUnsorted(start, end time.Time) Slice[time.Time] {
+	var out []time.Time
+	for _, t := range v.ж {
+		if !t.Before(start) && t.Before(end) {
+			out = append(out, t)
+		}
+	}
+	return SliceOf(out)
+}
+
+// Earliest returns the earliest time in v and true, or the zero time and
+// false if v is empty. It does not assume v is sorted.
+func (v TimeSlice) Earliest() (time.Time, bool) {
+	return SliceMinFunc(v.Slice, time.Time.Compare)
+}
+
+// Latest returns the latest time in v and true, or the zero time and false
+// if v is empty. It does not assume v is sorted.
+func (v TimeSlice) Latest() (time.Time, bool) {
+	return SliceMaxFunc(v.Slice, time.Time.Compare)
+}
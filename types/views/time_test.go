@@ -0,0 +1,70 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package views
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTimeSlice(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(mins ...int) []time.Time {
+		var out []time.Time
+		for _, m := range mins {
+			out = append(out, base.Add(time.Duration(m)*time.Minute))
+		}
+		return out
+	}
+	sorted := TimeSliceOf(at(0, 10, 10, 20, 30, 40))
+	unsorted := TimeSliceOf(at(30, 0, 40, 10, 20, 10))
+
+	tests := []struct {
+		name       string
+		start, end int // minutes
+		want       []time.Time
+	}{
+		{"in-range", 5, 35, at(10, 10, 20, 30)},
+		{"start-inclusive", 10, 21, at(10, 10, 20)},
+		{"end-exclusive", 0, 30, at(0, 10, 10, 20)},
+		{"all", -10, 100, at(0, 10, 10, 20, 30, 40)},
+		{"before", -20, -10, nil},
+		{"after", 50, 60, nil},
+		{"between", 11, 19, nil},
+		{"empty-window", 20, 20, nil},
+		{"inverted-window", 30, 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := base.Add(time.Duration(tt.start) * time.Minute)
+			end := base.Add(time.Duration(tt.end) * time.Minute)
+			if got := sorted.Within(start, end).AsSlice(); !slices.Equal(got, tt.want) {
+				t.Errorf("Within = %v; want %v", got, tt.want)
+			}
+			got := unsorted.WithinUnsorted(start, end).AsSlice()
+			slices.SortFunc(got, time.Time.Compare)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("WithinUnsorted = %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	if e, ok := unsorted.Earliest(); !ok || !e.Equal(base) {
+		t.Errorf("Earliest = %v, %v; want %v, true", e, ok, base)
+	}
+	if l, ok := unsorted.Latest(); !ok || !l.Equal(base.Add(40*time.Minute)) {
+		t.Errorf("Latest = %v, %v; want %v, true", l, ok, base.Add(40*time.Minute))
+	}
+	empty := TimeSliceOf(nil)
+	if _, ok := empty.Earliest(); ok {
+		t.Error("empty Earliest: got ok")
+	}
+	if _, ok := empty.Latest(); ok {
+		t.Error("empty Latest: got ok")
+	}
+	if got := empty.Within(base, base.Add(time.Hour)); got.Len() != 0 {
+		t.Errorf("empty Within = %v", got.AsSlice())
+	}
+}
@@ -3,15 +3,30 @@
 
 // Package views provides read-only accessors for commonly used
 // value types.
+//
+// Each view type has a constructor named after it: SliceOf, SliceViewOf,
+// ByteSliceOf, MapOf, MapFnOf and so on. The zero value of every view type
+// is a valid view of a nil value, so no separate "nil view" constructors
+// are needed.
 package views
 
 import (
 	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/maphash"
+	"io"
 	"maps"
+	"reflect"
 	"slices"
+	"strings"
+	"unsafe"
 
 	"go4.org/mem"
 )
@@ -58,6 +73,21 @@ func (v ByteSlice[T]) Mem() mem.RO {
 	return mem.B(v.ж)
 }
 
+// Base64 returns the standard base64 encoding of the underlying slice.
+func (v ByteSlice[T]) Base64() string {
+	return base64.StdEncoding.EncodeToString(v.ж)
+}
+
+// Hex returns the hexadecimal encoding of the underlying slice.
+func (v ByteSlice[T]) Hex() string {
+	return hex.EncodeToString(v.ж)
+}
+
+// Reader returns a reader over the underlying slice, without copying it.
+func (v ByteSlice[T]) Reader() *bytes.Reader {
+	return bytes.NewReader(v.ж)
+}
+
 // Equal reports whether the underlying slice is equal to b.
 func (v ByteSlice[T]) Equal(b T) bool {
 	return bytes.Equal(v.ж, b)
@@ -68,6 +98,44 @@ func (v ByteSlice[T]) EqualView(b ByteSlice[T]) bool {
 	return bytes.Equal(v.ж, b.ж)
 }
 
+// EqualMem reports whether the underlying slice is equal to m.
+// It does not allocate.
+func (v ByteSlice[T]) EqualMem(m mem.RO) bool {
+	return m.EqualBytes(v.ж)
+}
+
+// IndexByte returns the index of the first instance of b in the underlying
+// slice, or -1 if b is not present.
+func (v ByteSlice[T]) IndexByte(b byte) int {
+	return bytes.IndexByte(v.ж, b)
+}
+
+// ContainsByte reports whether b is within the underlying slice.
+func (v ByteSlice[T]) ContainsByte(b byte) bool {
+	return bytes.IndexByte(v.ж, b) >= 0
+}
+
+// HasPrefix reports whether the underlying slice begins with p.
+func (v ByteSlice[T]) HasPrefix(p T) bool {
+	return bytes.HasPrefix(v.ж, p)
+}
+
+// HasSuffix reports whether the underlying slice ends with p.
+func (v ByteSlice[T]) HasSuffix(p T) bool {
+	return bytes.HasSuffix(v.ж, p)
+}
+
+// TrimSpace returns a copy of the underlying slice with all leading and
+// trailing white space removed, as defined by Unicode.
+func (v ByteSlice[T]) TrimSpace() T {
+	return T(bytes.Clone(bytes.TrimSpace(v.ж)))
+}
+
+// HasPrefixView reports whether the underlying slice begins with p.
+func (v ByteSlice[T]) HasPrefixView(p ByteSlice[T]) bool {
+	return bytes.HasPrefix(v.ж, p.ж)
+}
+
 // AsSlice returns a copy of the underlying slice.
 func (v ByteSlice[T]) AsSlice() T {
 	return v.AppendTo(v.ж[:0:0])
@@ -127,6 +195,12 @@ func SliceOfViews[T ViewCloner[T, V], V StructView[T]](x []T) SliceView[T, V] {
 	return SliceView[T, V]{x}
 }
 
+// SliceViewOf is the same as SliceOfViews. It is named to match the other
+// view constructors such as SliceOf and MapOf.
+func SliceViewOf[T ViewCloner[T, V], V StructView[T]](x []T) SliceView[T, V] {
+	return SliceView[T, V]{x}
+}
+
 // SliceView wraps []T to provide accessors which return an immutable view V of
 // T. It is used to provide the equivalent of SliceOf([]V) without having to
 // allocate []V from []T.
@@ -144,6 +218,61 @@ func (v SliceView[T, V]) MarshalJSON() ([]byte, error) { return json.Marshal(v.
 // UnmarshalJSON implements json.Unmarshaler.
 func (v *SliceView[T, V]) UnmarshalJSON(b []byte) error { return unmarshalSliceFromJSON(b, &v.ж) }
 
+// ResetAndUnmarshalJSON is like UnmarshalJSON, but first resets v, so that
+// it can be used to decode into a view that already holds a value, such as
+// in a streaming decode loop. The previous underlying slice is not
+// modified, so other views of it are unaffected.
+func (v *SliceView[T, V]) ResetAndUnmarshalJSON(b []byte) error {
+	v.ж = nil
+	return v.UnmarshalJSON(b)
+}
+
+// UnmarshalSliceViewValidated is like dst.UnmarshalJSON(b), but additionally
+// calls validate with the view of each decoded element, in order. If
+// validate returns an error, UnmarshalSliceViewValidated returns it, annotated
+// with the element's index, and leaves dst unmodified.
+func UnmarshalSliceViewValidated[T ViewCloner[T, V], V StructView[T]](b []byte, dst *SliceView[T, V], validate func(V) error) error {
+	if dst.ж != nil {
+		return errors.New("already initialized")
+	}
+	var x []T
+	if err := unmarshalSliceFromJSON(b, &x); err != nil {
+		return err
+	}
+	for i, e := range x {
+		if err := validate(e.View()); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	dst.ж = x
+	return nil
+}
+
+// MarshalSliceViewProjected marshals v as a JSON array whose elements are
+// the result of calling project with the view of each element of v, in
+// order. It lets callers emit a reduced JSON shape, such as just one field
+// of each element, without building a parallel slice. A nil v is marshaled
+// as null.
+func MarshalSliceViewProjected[T ViewCloner[T, V], V StructView[T]](v SliceView[T, V], project func(V) any) ([]byte, error) {
+	if v.ж == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, x := range v.ж {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		b, err := json.Marshal(project(x.View()))
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
 // IsNil reports whether the underlying slice is nil.
 func (v SliceView[T, V]) IsNil() bool { return v.ж == nil }
 
@@ -153,6 +282,36 @@ func (v SliceView[T, V]) Len() int { return len(v.ж) }
 // At returns a View of the element at index `i` of the slice.
 func (v SliceView[T, V]) At(i int) V { return v.ж[i].View() }
 
+// ForEach calls f with the index and view of each element in order.
+// It stops iteration immediately if f returns false.
+func (v SliceView[T, V]) ForEach(f func(i int, e V) (cont bool)) {
+	for i, x := range v.ж {
+		if !f(i, x.View()) {
+			return
+		}
+	}
+}
+
+// IndexFunc returns the index of the first element in v whose view satisfies
+// f, or -1 if none do.
+//
+// As it runs in O(n) time, use with care.
+func (v SliceView[T, V]) IndexFunc(f func(V) bool) int {
+	for i, x := range v.ж {
+		if f(x.View()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ContainsFunc reports whether the view of any element in v satisfies f.
+//
+// As it runs in O(n) time, use with care.
+func (v SliceView[T, V]) ContainsFunc(f func(V) bool) bool {
+	return v.IndexFunc(f) >= 0
+}
+
 // SliceFrom returns v[i:].
 func (v SliceView[T, V]) SliceFrom(i int) SliceView[T, V] { return SliceView[T, V]{v.ж[i:]} }
 
@@ -166,7 +325,18 @@ func (v SliceView[T, V]) Slice(i, j int) SliceView[T, V] { return SliceView[T, V
 // address and length. It can be used to key maps by slices but should only be
 // used when the underlying slice is immutable.
 //
-// Empty and nil slices have different keys.
+// Two keys are equal exactly when their slices start at the same element
+// address and have the same length. In particular:
+//
+//   - Empty and nil slices have different keys.
+//   - All empty slices have the same key, whatever their backing array.
+//   - Subslices of one backing array with the same start and length have the
+//     same key, even if their capacities differ or they were obtained by
+//     reslicing different views.
+//   - Overlapping subslices with a different start or length have different
+//     keys.
+//   - Slices with equal contents in different backing arrays have different
+//     keys.
 type SliceMapKey[T any] struct {
 	// t is the address of the first element, or nil if the slice is nil or
 	// empty.
@@ -175,6 +345,12 @@ type SliceMapKey[T any] struct {
 	n int
 }
 
+// Equal reports whether k and o are keys for the same slice, as described
+// in the SliceMapKey docs. It is equivalent to k == o.
+func (k SliceMapKey[T]) Equal(o SliceMapKey[T]) bool {
+	return k == o
+}
+
 // MapKey returns a unique key for a slice, based on its address and length.
 func (v SliceView[T, V]) MapKey() SliceMapKey[T] { return mapKey(v.ж) }
 
@@ -191,6 +367,49 @@ func (v SliceView[T, V]) AsSlice() []V {
 	return v.AppendTo(nil)
 }
 
+// AppendSliceViewFunc appends f(e) for the view e of each element of v to
+// dst and returns it. It is like SliceView.AppendTo, but for a value derived
+// from each view, and avoids materializing a []V just to map it. (It is a
+// function rather than a method because methods can't have type
+// parameters.)
+func AppendSliceViewFunc[T ViewCloner[T, V], V StructView[T], R any](dst []R, v SliceView[T, V], f func(V) R) []R {
+	for _, x := range v.ж {
+		dst = append(dst, f(x.View()))
+	}
+	return dst
+}
+
+// SliceViewIndexBy returns a map from key(e) to e for the view e of each
+// element of v, such as to turn a list of nodes into a map by node ID. If
+// more than one element has the same key, the last one in v wins. It
+// returns nil if v is empty.
+func SliceViewIndexBy[T ViewCloner[T, V], V StructView[T], K comparable](v SliceView[T, V], key func(V) K) map[K]V {
+	if len(v.ж) == 0 {
+		return nil
+	}
+	m := make(map[K]V, len(v.ж))
+	for _, x := range v.ж {
+		e := x.View()
+		m[key(e)] = e
+	}
+	return m
+}
+
+// SliceViewsEqual reports whether a and b have the same length and eq
+// reports true for each pair of corresponding element views. It is
+// SliceEqual for slices of view types, which aren't directly comparable.
+func SliceViewsEqual[T ViewCloner[T, V], V StructView[T]](a, b SliceView[T, V], eq func(V, V) bool) bool {
+	if len(a.ж) != len(b.ж) {
+		return false
+	}
+	for i := range a.ж {
+		if !eq(a.ж[i].View(), b.ж[i].View()) {
+			return false
+		}
+	}
+	return true
+}
+
 // Slice is a read-only accessor for a slice.
 type Slice[T any] struct {
 	// ж is the underlying mutable value, named with a hard-to-type
@@ -214,6 +433,28 @@ func mapKey[T any](x []T) SliceMapKey[T] {
 	return SliceMapKey[T]{&x[0], len(x)}
 }
 
+// sliceHashSeed is the per-process seed used by SliceHashKey.
+var sliceHashSeed = maphash.MakeSeed()
+
+// SliceHashKey returns a hash of the contents of v, computed by folding each
+// element into a maphash.Hash with hashElem. Unlike SliceMapKey, slices with
+// equal contents have equal keys even if they have different backing arrays.
+//
+// The hash is seeded once per process, so keys must not be persisted or
+// compared across processes. As with any hash, distinct contents may
+// collide.
+func SliceHashKey[T any](v Slice[T], hashElem func(T, *maphash.Hash)) uint64 {
+	var h maphash.Hash
+	h.SetSeed(sliceHashSeed)
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(len(v.ж)))
+	h.Write(n[:])
+	for _, x := range v.ж {
+		hashElem(x, &h)
+	}
+	return h.Sum64()
+}
+
 // SliceOf returns a Slice for the provided slice for immutable values.
 // It is the caller's responsibility to make sure V is immutable.
 func SliceOf[T any](x []T) Slice[T] {
@@ -230,6 +471,15 @@ func (v *Slice[T]) UnmarshalJSON(b []byte) error {
 	return unmarshalSliceFromJSON(b, &v.ж)
 }
 
+// ResetAndUnmarshalJSON is like UnmarshalJSON, but first resets v, so that
+// it can be used to decode into a view that already holds a value, such as
+// in a streaming decode loop. The previous underlying slice is not
+// modified, so other views of it are unaffected.
+func (v *Slice[T]) ResetAndUnmarshalJSON(b []byte) error {
+	v.ж = nil
+	return v.UnmarshalJSON(b)
+}
+
 // IsNil reports whether the underlying slice is nil.
 func (v Slice[T]) IsNil() bool { return v.ж == nil }
 
@@ -239,6 +489,16 @@ func (v Slice[T]) Len() int { return len(v.ж) }
 // At returns the element at index `i` of the slice.
 func (v Slice[T]) At(i int) T { return v.ж[i] }
 
+// ForEach calls f with the index and value of each element in order.
+// It stops iteration immediately if f returns false.
+func (v Slice[T]) ForEach(f func(i int, e T) (cont bool)) {
+	for i, x := range v.ж {
+		if !f(i, x) {
+			return
+		}
+	}
+}
+
 // SliceFrom returns v[i:].
 func (v Slice[T]) SliceFrom(i int) Slice[T] { return Slice[T]{v.ж[i:]} }
 
@@ -258,6 +518,22 @@ func (v Slice[T]) AsSlice() []T {
 	return v.AppendTo(v.ж[:0:0])
 }
 
+// Clone returns a view of a shallow-clone of the underlying slice, so that
+// later changes to the slice v views don't affect the returned view. Views
+// are otherwise cheap to copy but share their backing array: copying v, or
+// passing the same slice to SliceOf again, observes any later mutation.
+//
+// Use Clone to take a snapshot of a slice whose owner may keep writing to
+// it, such as by appending within its capacity, which would otherwise
+// overwrite elements past the end of v that a later resliced view could
+// observe.
+//
+// If T is a pointer type, it is the caller's responsibility to make sure
+// the elements are immutable.
+func (v Slice[T]) Clone() Slice[T] {
+	return Slice[T]{v.AsSlice()}
+}
+
 // IndexFunc returns the first index of an element in v satisfying f(e),
 // or -1 if none do.
 //
@@ -271,6 +547,60 @@ func (v Slice[T]) IndexFunc(f func(T) bool) int {
 	return -1
 }
 
+// Filter returns a new slice containing the elements of v for which keep
+// returns true, in order. The result is a copy that is safe to mutate; it is
+// nil if no elements are kept.
+func (v Slice[T]) Filter(keep func(T) bool) []T {
+	var out []T
+	for _, x := range v.ж {
+		if keep(x) {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// CountFunc returns the number of elements in v for which f returns true.
+func (v Slice[T]) CountFunc(f func(T) bool) int {
+	var n int
+	for _, x := range v.ж {
+		if f(x) {
+			n++
+		}
+	}
+	return n
+}
+
+// Partition splits v into the elements for which f returns true and those
+// for which it returns false, preserving their order in v. Both results are
+// copies that are safe to mutate; like Filter, each is nil if it would be
+// empty.
+func (v Slice[T]) Partition(f func(T) bool) (match, rest []T) {
+	for _, x := range v.ж {
+		if f(x) {
+			match = append(match, x)
+		} else {
+			rest = append(rest, x)
+		}
+	}
+	return match, rest
+}
+
+// IndexFuncFrom is like IndexFunc, but starts the search at index start,
+// which is clamped to zero if negative. It returns the index in v of the
+// first element at or after start satisfying f(e), or -1 if none do,
+// including when start is past the end of v.
+//
+// As it runs in O(n) time, use with care.
+func (v Slice[T]) IndexFuncFrom(start int, f func(T) bool) int {
+	for i := max(start, 0); i < len(v.ж); i++ {
+		if f(v.ж[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
 // ContainsFunc reports whether any element in v satisfies f(e).
 //
 // As it runs in O(n) time, use with care.
@@ -278,6 +608,20 @@ func (v Slice[T]) ContainsFunc(f func(T) bool) bool {
 	return slices.ContainsFunc(v.ж, f)
 }
 
+// AppendDelimited appends f(e) for each element e in v to dst, separated by
+// sep, and returns the extended buffer. It's useful for writing rows of
+// delimited output such as CSV without allocating intermediate slices. It
+// does not quote or escape the strings returned by f.
+func (v Slice[T]) AppendDelimited(dst []byte, sep byte, f func(T) string) []byte {
+	for i, x := range v.ж {
+		if i > 0 {
+			dst = append(dst, sep)
+		}
+		dst = append(dst, f(x)...)
+	}
+	return dst
+}
+
 // AppendStrings appends the string representation of each element in v to dst.
 func AppendStrings[T fmt.Stringer](dst []string, v Slice[T]) []string {
 	for _, x := range v.ж {
@@ -286,6 +630,124 @@ func AppendStrings[T fmt.Stringer](dst []string, v Slice[T]) []string {
 	return dst
 }
 
+// DecodeSliceStream decodes a JSON array from r, calling each for every
+// element in order. Unlike unmarshaling into a Slice, elements are not
+// retained, so arbitrarily large arrays can be processed in constant memory.
+//
+// Decoding stops at the first error returned by each, which is returned as-is.
+// A JSON null is treated as an empty array.
+func DecodeSliceStream[T any](r io.Reader, each func(T) error) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("expected JSON array, got %v", tok)
+	}
+	for dec.More() {
+		var e T
+		if err := dec.Decode(&e); err != nil {
+			return err
+		}
+		if err := each(e); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // consume closing ']'
+		return err
+	}
+	return nil
+}
+
+// SliceMarshalBinary returns the binary encoding of v: the concatenation of
+// each element's MarshalBinary output, each prefixed by its length as a
+// uvarint. It can be decoded with DecodeSliceBinary.
+func SliceMarshalBinary[T encoding.BinaryMarshaler](v Slice[T]) ([]byte, error) {
+	var b []byte
+	for _, x := range v.ж {
+		eb, err := x.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		b = binary.AppendUvarint(b, uint64(len(eb)))
+		b = append(b, eb...)
+	}
+	return b, nil
+}
+
+// DecodeSliceBinary decodes b, as encoded by SliceMarshalBinary, into a new
+// Slice, using *T's UnmarshalBinary method for each element.
+func DecodeSliceBinary[T any, PT interface {
+	*T
+	encoding.BinaryUnmarshaler
+}](b []byte) (Slice[T], error) {
+	var out []T
+	for len(b) > 0 {
+		n, sz := binary.Uvarint(b)
+		if sz <= 0 {
+			return Slice[T]{}, errors.New("invalid element length")
+		}
+		b = b[sz:]
+		if n > uint64(len(b)) {
+			return Slice[T]{}, fmt.Errorf("element length %d exceeds remaining %d bytes", n, len(b))
+		}
+		var e T
+		if err := PT(&e).UnmarshalBinary(b[:n]); err != nil {
+			return Slice[T]{}, err
+		}
+		out = append(out, e)
+		b = b[n:]
+	}
+	return SliceOf(out), nil
+}
+
+// SliceJoinStrings returns the String form of each element in v, separated
+// by sep.
+func SliceJoinStrings[T fmt.Stringer](v Slice[T], sep string) string {
+	return SliceJoin(v, sep, T.String)
+}
+
+// SliceJoin returns f applied to each element in v, separated by sep.
+func SliceJoin[T any](v Slice[T], sep string, f func(T) string) string {
+	var sb strings.Builder
+	for i, x := range v.ж {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(f(x))
+	}
+	return sb.String()
+}
+
+// StringsJoin is like the standard library's strings.Join, but for a view of
+// strings. It builds the result directly from v, in a single allocation,
+// without copying v to a []string first.
+func StringsJoin(v Slice[string], sep string) string {
+	switch len(v.ж) {
+	case 0:
+		return ""
+	case 1:
+		return v.ж[0]
+	}
+	n := len(sep) * (len(v.ж) - 1)
+	for _, s := range v.ж {
+		n += len(s)
+	}
+	var sb strings.Builder
+	sb.Grow(n)
+	for i, s := range v.ж {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
 // SliceContains reports whether v contains element e.
 //
 // As it runs in O(n) time, use with care.
@@ -293,11 +755,168 @@ func SliceContains[T comparable](v Slice[T], e T) bool {
 	return slices.Contains(v.ж, e)
 }
 
+// SliceFind returns the first element of v satisfying f and true, or the
+// zero value and false if none do.
+//
+// As it runs in O(n) time, use with care.
+func SliceFind[T any](v Slice[T], f func(T) bool) (T, bool) {
+	for _, x := range v.ж {
+		if f(x) {
+			return x, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// SliceCount returns the number of elements in v equal to e.
+//
+// As it runs in O(n) time, use with care.
+func SliceCount[T comparable](v Slice[T], e T) int {
+	var n int
+	for _, x := range v.ж {
+		if x == e {
+			n++
+		}
+	}
+	return n
+}
+
+// SliceConcat is like the standard library's slices.Concat, but for views.
+// It returns a newly allocated slice holding the elements of each of vs in
+// order, or nil if they are all empty.
+func SliceConcat[T any](vs ...Slice[T]) []T {
+	var n int
+	for _, v := range vs {
+		n += len(v.ж)
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make([]T, 0, n)
+	for _, v := range vs {
+		out = append(out, v.ж...)
+	}
+	return out
+}
+
 // SliceEqual is like the standard library's slices.Equal, but for two views.
 func SliceEqual[T comparable](a, b Slice[T]) bool {
 	return slices.Equal(a.ж, b.ж)
 }
 
+// SliceEqualFunc is like the standard library's slices.EqualFunc, but for
+// two views.
+func SliceEqualFunc[T any](a, b Slice[T], eq func(T, T) bool) bool {
+	return slices.EqualFunc(a.ж, b.ж, eq)
+}
+
+// SliceDiffIndex returns the index of the first element at which a and b
+// differ, or -1 if they are equal. If one is a prefix of the other, it
+// returns the length of the shorter one.
+func SliceDiffIndex[T comparable](a, b Slice[T]) int {
+	n := min(len(a.ж), len(b.ж))
+	for i := range n {
+		if a.ж[i] != b.ж[i] {
+			return i
+		}
+	}
+	if len(a.ж) != len(b.ж) {
+		return n
+	}
+	return -1
+}
+
+// SliceHasPrefix reports whether v begins with prefix.
+func SliceHasPrefix[T comparable](v, prefix Slice[T]) bool {
+	return len(v.ж) >= len(prefix.ж) && slices.Equal(v.ж[:len(prefix.ж)], prefix.ж)
+}
+
+// SliceIsSorted is like the standard library's slices.IsSorted, but for a
+// view. It reports whether v is sorted in increasing order, and is true for
+// empty and single-element views.
+func SliceIsSorted[T cmp.Ordered](v Slice[T]) bool {
+	return slices.IsSorted(v.ж)
+}
+
+// SliceIsSortedFunc is like SliceIsSorted, but uses cmp to compare
+// elements.
+func SliceIsSortedFunc[T any](v Slice[T], cmp func(a, b T) int) bool {
+	return slices.IsSortedFunc(v.ж, cmp)
+}
+
+// SliceBinarySearch is like the standard library's slices.BinarySearch, but
+// for a view. It searches for target in v, which must be sorted in
+// increasing order, and returns the position where target is found, or
+// where it would appear in sort order, and whether it was found.
+func SliceBinarySearch[T cmp.Ordered](v Slice[T], target T) (int, bool) {
+	return slices.BinarySearch(v.ж, target)
+}
+
+// SliceBinarySearchFunc is like SliceBinarySearch, but uses cmp to compare
+// elements. v must be sorted in increasing order as defined by cmp.
+func SliceBinarySearchFunc[T any](v Slice[T], target T, cmp func(T, T) int) (int, bool) {
+	return slices.BinarySearchFunc(v.ж, target, cmp)
+}
+
+// SliceMax returns the maximal element in v and true, or the zero value and
+// false if v is empty. Unlike slices.Max, it does not panic on empty input.
+func SliceMax[T cmp.Ordered](v Slice[T]) (T, bool) {
+	if len(v.ж) == 0 {
+		var zero T
+		return zero, false
+	}
+	return slices.Max(v.ж), true
+}
+
+// SliceMin returns the minimal element in v and true, or the zero value and
+// false if v is empty. Unlike slices.Min, it does not panic on empty input.
+func SliceMin[T cmp.Ordered](v Slice[T]) (T, bool) {
+	if len(v.ж) == 0 {
+		var zero T
+		return zero, false
+	}
+	return slices.Min(v.ж), true
+}
+
+// SliceMaxFunc is like SliceMax, but uses cmp to compare elements.
+// If there is more than one maximal element, it returns the first one.
+func SliceMaxFunc[T any](v Slice[T], cmp func(a, b T) int) (T, bool) {
+	if len(v.ж) == 0 {
+		var zero T
+		return zero, false
+	}
+	return slices.MaxFunc(v.ж, cmp), true
+}
+
+// SliceMinFunc is like SliceMin, but uses cmp to compare elements.
+// If there is more than one minimal element, it returns the first one.
+func SliceMinFunc[T any](v Slice[T], cmp func(a, b T) int) (T, bool) {
+	if len(v.ж) == 0 {
+		var zero T
+		return zero, false
+	}
+	return slices.MinFunc(v.ж, cmp), true
+}
+
+// SliceSum returns the sum of the elements in v, or zero if v is empty.
+func SliceSum[T Number](v Slice[T]) T {
+	var sum T
+	for _, x := range v.ж {
+		sum += x
+	}
+	return sum
+}
+
+// SliceAvg returns the mean of the elements in v as a float64, or zero if v
+// is empty.
+func SliceAvg[T Number](v Slice[T]) float64 {
+	if len(v.ж) == 0 {
+		return 0
+	}
+	return float64(SliceSum(v)) / float64(len(v.ж))
+}
+
 // SliceEqualAnyOrder reports whether a and b contain the same elements, regardless of order.
 // The underlying slices for a and b can be nil.
 func SliceEqualAnyOrder[T comparable](a, b Slice[T]) bool {
@@ -329,6 +948,85 @@ func SliceEqualAnyOrder[T comparable](a, b Slice[T]) bool {
 	return true
 }
 
+// SliceUniq returns a view over a new slice containing the elements of v
+// with all duplicates removed, keeping the first occurrence of each in
+// order. Unlike slices.Compact, duplicates need not be adjacent.
+//
+// It always allocates a new slice and a set of seen elements, even if v has
+// no duplicates.
+func SliceUniq[T comparable](v Slice[T]) Slice[T] {
+	return SliceUniqFunc(v, func(e T) T { return e })
+}
+
+// SliceUniqFunc is like SliceUniq, but considers two elements duplicates if
+// key returns the same value for both.
+func SliceUniqFunc[T any, K comparable](v Slice[T], key func(T) K) Slice[T] {
+	if len(v.ж) == 0 {
+		return Slice[T]{}
+	}
+	seen := make(map[K]bool, len(v.ж))
+	out := make([]T, 0, len(v.ж))
+	for _, e := range v.ж {
+		k := key(e)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, e)
+	}
+	return Slice[T]{out}
+}
+
+// StackOf returns a Stack view over v, whose last element is the top of
+// the stack.
+func StackOf[T any](v Slice[T]) Stack[T] {
+	return Stack[T]{v}
+}
+
+// Stack is a read-only last-in-first-out view over a Slice, whose last
+// element is the top of the stack.
+type Stack[T any] struct {
+	v Slice[T]
+}
+
+// Len returns the number of elements in the stack.
+func (s Stack[T]) Len() int { return s.v.Len() }
+
+// Peek returns the top element of the stack and true, or the zero value and
+// false if the stack is empty.
+func (s Stack[T]) Peek() (T, bool) {
+	if s.v.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.v.At(s.v.Len() - 1), true
+}
+
+// QueueOf returns a Queue view over v, whose first element is the front of
+// the queue.
+func QueueOf[T any](v Slice[T]) Queue[T] {
+	return Queue[T]{v}
+}
+
+// Queue is a read-only first-in-first-out view over a Slice, whose first
+// element is the front of the queue.
+type Queue[T any] struct {
+	v Slice[T]
+}
+
+// Len returns the number of elements in the queue.
+func (q Queue[T]) Len() int { return q.v.Len() }
+
+// Peek returns the front element of the queue and true, or the zero value
+// and false if the queue is empty.
+func (q Queue[T]) Peek() (T, bool) {
+	if q.v.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.v.At(0), true
+}
+
 // MapOf returns a view over m. It is the caller's responsibility to make sure K
 // and V is immutable, if this is being used to provide a read-only view over m.
 func MapOf[K comparable, V comparable](m map[K]V) Map[K, V] {
@@ -370,7 +1068,36 @@ func (m Map[K, V]) GetOk(k K) (V, bool) {
 	return v, ok
 }
 
+// GetOr returns the value for k, or def if k is not present in m.
+func (m Map[K, V]) GetOr(k K, def V) V {
+	if v, ok := m.ж[k]; ok {
+		return v
+	}
+	return def
+}
+
+// MapViewKey represents a comparable unique key for a map, based on its
+// identity rather than its contents. It can be used to key caches by map
+// views.
+//
+// A key is only meaningful while the view's underlying map is in use; if the
+// owner replaces the map with a new one, even with the same contents, the
+// key changes. Keys of nil maps are equal to each other.
+type MapViewKey struct {
+	p unsafe.Pointer // the underlying map, or nil
+}
+
+// MapKey returns a unique key for the map, based on the identity of the
+// underlying map. Views over the same map have equal keys.
+func (m Map[K, V]) MapKey() MapViewKey {
+	return MapViewKey{reflect.ValueOf(m.ж).UnsafePointer()}
+}
+
 // MarshalJSON implements json.Marshaler.
+//
+// The output is deterministic: like encoding/json for any map, it emits
+// keys sorted by their JSON string form, including for integer and
+// encoding.TextMarshaler keys.
 func (m Map[K, V]) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m.ж)
 }
@@ -384,6 +1111,15 @@ func (m *Map[K, V]) UnmarshalJSON(b []byte) error {
 	return json.Unmarshal(b, &m.ж)
 }
 
+// ResetAndUnmarshalJSON is like UnmarshalJSON, but first resets m, so that
+// it can be used to decode into a view that already holds a value, such as
+// in a streaming decode loop. The previous underlying map is not modified,
+// so other views of it are unaffected.
+func (m *Map[K, V]) ResetAndUnmarshalJSON(b []byte) error {
+	m.ж = nil
+	return m.UnmarshalJSON(b)
+}
+
 // AsMap returns a shallow-clone of the underlying map.
 // If V is a pointer type, it is the caller's responsibility to make sure
 // the values are immutable.
@@ -394,6 +1130,30 @@ func (m Map[K, V]) AsMap() map[K]V {
 	return maps.Clone(m.ж)
 }
 
+// CloneView returns a view of a shallow-clone of the underlying map, so that
+// later changes to the map m views don't affect the returned view.
+// If V is a pointer type, it is the caller's responsibility to make sure
+// the values are immutable.
+func (m Map[K, V]) CloneView() Map[K, V] {
+	return Map[K, V]{m.AsMap()}
+}
+
+// Filter returns a new map containing the entries of m for which keep
+// returns true. It returns nil if m is nil, and an empty non-nil map if m
+// is non-nil but no entries are kept, mirroring AsMap.
+func (m Map[K, V]) Filter(keep func(K, V) bool) map[K]V {
+	if m.ж == nil {
+		return nil
+	}
+	out := map[K]V{}
+	for k, v := range m.ж {
+		if keep(k, v) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
 // MapRangeFn is the func called from a Map.Range call.
 // Implementations should return false to stop range.
 type MapRangeFn[K comparable, V any] func(k K, v V) (cont bool)
@@ -408,6 +1168,147 @@ func (m Map[K, V]) Range(f MapRangeFn[K, V]) {
 	}
 }
 
+// RangeErr calls f for every k,v pair in the underlying map, in unspecified
+// order. It stops iteration immediately and returns the error if f returns
+// a non-nil error.
+func (m Map[K, V]) RangeErr(f func(k K, v V) error) error {
+	for k, v := range m.ж {
+		if err := f(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// KV is a key-value pair from a Map.
+type KV[K comparable, V any] struct {
+	K K
+	V V
+}
+
+// MapCloneDeep returns a new map with the same keys as m and a deep clone
+// of each value, made with its Clone method, so that no values are shared
+// with m. Unlike AsMap, it is safe to mutate the returned values. It returns
+// nil if m is nil.
+func MapCloneDeep[K comparable, T ViewCloner[T, V], V StructView[T]](m Map[K, T]) map[K]T {
+	if m.ж == nil {
+		return nil
+	}
+	out := make(map[K]T, len(m.ж))
+	for k, v := range m.ж {
+		out[k] = v.Clone()
+	}
+	return out
+}
+
+// MapMerge copies every entry of each of views into dst, with later views
+// taking precedence over earlier ones and over dst's existing entries. If
+// dst is nil, a new map is allocated. It returns dst. Nil views are
+// skipped.
+func MapMerge[K comparable, V any](dst map[K]V, views ...Map[K, V]) map[K]V {
+	if dst == nil {
+		dst = map[K]V{}
+	}
+	for _, m := range views {
+		maps.Copy(dst, m.ж)
+	}
+	return dst
+}
+
+// MapEqual reports whether a and b contain the same key/value pairs. As with
+// maps.Equal and SliceEqual, a nil map and an empty map are equal.
+func MapEqual[K, V comparable](a, b Map[K, V]) bool {
+	return maps.Equal(a.ж, b.ж)
+}
+
+// MapEqualFunc is like MapEqual, but compares values using eq.
+func MapEqualFunc[K comparable, V any](a, b Map[K, V], eq func(V, V) bool) bool {
+	return maps.EqualFunc(a.ж, b.ж, eq)
+}
+
+// MapSortedKeys returns the keys of m in a new slice, sorted in ascending
+// order, for callers that need a deterministic order, such as logging and
+// diffing. It returns nil if m is nil.
+func MapSortedKeys[K cmp.Ordered, V any](m Map[K, V]) []K {
+	if m.ж == nil {
+		return nil
+	}
+	keys := make([]K, 0, len(m.ж))
+	for k := range m.ж {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// MapEntriesSorted returns the entries of m sorted by key, for callers that
+// need a deterministic order, such as CLI output. It returns nil if m is
+// empty.
+func MapEntriesSorted[K cmp.Ordered, V any](m Map[K, V]) []KV[K, V] {
+	if len(m.ж) == 0 {
+		return nil
+	}
+	kvs := make([]KV[K, V], 0, len(m.ж))
+	for k, v := range m.ж {
+		kvs = append(kvs, KV[K, V]{k, v})
+	}
+	slices.SortFunc(kvs, func(a, b KV[K, V]) int { return cmp.Compare(a.K, b.K) })
+	return kvs
+}
+
+// MapToSortedSlice returns a view over a new slice containing project(k, v)
+// for each entry of m, sorted by less. Since m is unordered, the relative
+// order of elements that compare equal is unspecified; use a less function
+// that orders all elements if the order must be deterministic.
+func MapToSortedSlice[K comparable, V any, U any](m Map[K, V], project func(K, V) U, less func(U, U) bool) Slice[U] {
+	if len(m.ж) == 0 {
+		return Slice[U]{}
+	}
+	out := make([]U, 0, len(m.ж))
+	for k, v := range m.ж {
+		out = append(out, project(k, v))
+	}
+	slices.SortFunc(out, func(a, b U) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	})
+	return SliceOf(out)
+}
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// MapSumInto adds each value in src to the value for the same key in dst,
+// adding keys missing from dst.
+func MapSumInto[K comparable, V Number](dst map[K]V, src Map[K, V]) {
+	for k, v := range src.ж {
+		dst[k] += v
+	}
+}
+
+// MapSum returns a view over a new map holding, for each key in any of ms,
+// the sum of its values across ms.
+func MapSum[K comparable, V Number](ms ...Map[K, V]) Map[K, V] {
+	n := 0
+	for _, m := range ms {
+		n = max(n, m.Len())
+	}
+	sum := make(map[K]V, n)
+	for _, m := range ms {
+		MapSumInto(sum, m)
+	}
+	return Map[K, V]{sum}
+}
+
 // MapFnOf returns a MapFn for m.
 func MapFnOf[K comparable, T any, V any](m map[K]T, f func(T) V) MapFn[K, T, V] {
 	return MapFn[K, T, V]{
@@ -453,6 +1354,15 @@ func (m MapFn[K, T, V]) GetOk(k K) (V, bool) {
 	return m.wrapv(v), ok
 }
 
+// GetOr returns the wrapped value for k, or def if k is not present in m.
+// Unlike Get, it does not call the wrapping function for absent keys.
+func (m MapFn[K, T, V]) GetOr(k K, def V) V {
+	if v, ok := m.ж[k]; ok {
+		return m.wrapv(v)
+	}
+	return def
+}
+
 // Range calls f for every k,v pair in the underlying map.
 // It stops iteration immediately if f returns false.
 func (m MapFn[K, T, V]) Range(f MapRangeFn[K, V]) {
@@ -462,3 +1372,34 @@ func (m MapFn[K, T, V]) Range(f MapRangeFn[K, V]) {
 		}
 	}
 }
+
+// AsMap returns a new map with the same keys as the underlying map, and each
+// value converted with the MapFn's conversion func. It returns nil if the
+// underlying map is nil.
+func (m MapFn[K, T, V]) AsMap() map[K]V {
+	if m.ж == nil {
+		return nil
+	}
+	out := make(map[K]V, len(m.ж))
+	for k, v := range m.ж {
+		out[k] = m.wrapv(v)
+	}
+	return out
+}
+
+// Filter returns a new map containing the converted values of the entries
+// of m for which keep returns true. Each value is converted exactly once,
+// before keep is called. Like Map.Filter, it returns nil if the underlying
+// map is nil, and an empty non-nil map if no entries are kept.
+func (m MapFn[K, T, V]) Filter(keep func(K, V) bool) map[K]V {
+	if m.ж == nil {
+		return nil
+	}
+	out := map[K]V{}
+	for k, v := range m.ж {
+		if wv := m.wrapv(v); keep(k, wv) {
+			out[k] = wv
+		}
+	}
+	return out
+}
@@ -5,13 +5,22 @@
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"maps"
 	"net/netip"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
+	"go4.org/mem"
 )
 
 type viewStruct struct {
@@ -139,6 +148,174 @@ func TestViewUtils(t *testing.T) {
 		qt.Equals, true)
 }
 
+func TestByteSliceEncodings(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         []byte
+		wantBase64 string
+		wantHex    string
+	}{
+		{"nil", nil, "", ""},
+		{"empty", []byte{}, "", ""},
+		{"vector", []byte("foobar"), "Zm9vYmFy", "666f6f626172"},
+		{"binary", []byte{0x00, 0xff, 0x10}, "AP8Q", "00ff10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := ByteSliceOf(tt.in)
+			if got := v.Base64(); got != tt.wantBase64 {
+				t.Errorf("Base64 = %q; want %q", got, tt.wantBase64)
+			}
+			if got := v.Hex(); got != tt.wantHex {
+				t.Errorf("Hex = %q; want %q", got, tt.wantHex)
+			}
+		})
+	}
+}
+
+func TestByteSliceEqualMem(t *testing.T) {
+	tests := []struct {
+		name string
+		v    []byte
+		m    mem.RO
+		want bool
+	}{
+		{"equal", []byte("foo"), mem.S("foo"), true},
+		{"different", []byte("foo"), mem.S("bar"), false},
+		{"different_length", []byte("foo"), mem.S("foobar"), false},
+		{"empty", []byte{}, mem.S(""), true},
+		{"nil_vs_empty", nil, mem.B([]byte{}), true},
+		{"empty_vs_non_empty", nil, mem.S("x"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ByteSliceOf(tt.v).EqualMem(tt.m); got != tt.want {
+				t.Errorf("EqualMem = %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	v := ByteSliceOf([]byte("hello"))
+	m := mem.S("hello")
+	if n := testing.AllocsPerRun(100, func() { v.EqualMem(m) }); n != 0 {
+		t.Errorf("EqualMem allocs = %v; want 0", n)
+	}
+}
+
+func TestByteSliceIndexByte(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		b    byte
+		want int
+	}{
+		{nil, 'a', -1},
+		{[]byte("hello"), 'l', 2},
+		{[]byte("hello"), 'o', 4},
+		{[]byte("hello"), 'z', -1},
+		{[]byte("a\x00b\x00"), 0, 1},
+		{[]byte("line\n"), '\n', 4},
+	}
+	for _, tt := range tests {
+		v := ByteSliceOf(tt.in)
+		if got := v.IndexByte(tt.b); got != tt.want {
+			t.Errorf("IndexByte(%q, %q) = %d; want %d", tt.in, tt.b, got, tt.want)
+		}
+		if got, want := v.ContainsByte(tt.b), tt.want >= 0; got != want {
+			t.Errorf("ContainsByte(%q, %q) = %v; want %v", tt.in, tt.b, got, want)
+		}
+	}
+}
+
+func TestByteSliceTrimSpaceAffixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantTrim string
+	}{
+		{"empty", "", ""},
+		{"whitespace_only", " \t\r\n ", ""},
+		{"mixed", "\t key = value \n", "key = value"},
+		{"no_whitespace", "abc", "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ByteSliceOf([]byte(tt.in)).TrimSpace(); string(got) != tt.wantTrim {
+				t.Errorf("TrimSpace = %q; want %q", got, tt.wantTrim)
+			}
+		})
+	}
+
+	in := []byte("  abc  ")
+	got := ByteSliceOf(in).TrimSpace()
+	got[0] = 'X'
+	if string(in) != "  abc  " {
+		t.Errorf("mutating TrimSpace result modified the underlying slice: %q", in)
+	}
+
+	v := ByteSliceOf([]byte("# comment\n"))
+	for _, tt := range []struct {
+		p            string
+		prefix, suff bool
+	}{
+		{"", true, true},
+		{"#", true, false},
+		{"# comment\n", true, true},
+		{"\n", false, true},
+		{"comment", false, false},
+		{"# comment\n!", false, false},
+	} {
+		if got := v.HasPrefix([]byte(tt.p)); got != tt.prefix {
+			t.Errorf("HasPrefix(%q) = %v; want %v", tt.p, got, tt.prefix)
+		}
+		if got := v.HasSuffix([]byte(tt.p)); got != tt.suff {
+			t.Errorf("HasSuffix(%q) = %v; want %v", tt.p, got, tt.suff)
+		}
+	}
+}
+
+func TestByteSliceReader(t *testing.T) {
+	v := ByteSliceOf([]byte("hello, world"))
+	got, err := io.ReadAll(v.Reader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, v.AsSlice()) {
+		t.Errorf("read %q; want %q", got, v.AsSlice())
+	}
+
+	got, err = io.ReadAll(v.SliceFrom(7).Reader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "world" {
+		t.Errorf("read %q from sub-slice; want %q", got, "world")
+	}
+
+	var empty ByteSlice[[]byte]
+	if n, err := empty.Reader().Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("empty Read = %d, %v; want 0, EOF", n, err)
+	}
+}
+
+func TestSliceCount(t *testing.T) {
+	v := SliceOf([]string{"a", "b", "a", "c", "a"})
+	for _, tt := range []struct {
+		e    string
+		want int
+	}{
+		{"z", 0},
+		{"b", 1},
+		{"a", 3},
+	} {
+		if got := SliceCount(v, tt.e); got != tt.want {
+			t.Errorf("SliceCount(%q) = %d; want %d", tt.e, got, tt.want)
+		}
+	}
+	if got := SliceCount(SliceOf[string](nil), "a"); got != 0 {
+		t.Errorf("SliceCount on nil slice = %d; want 0", got)
+	}
+}
+
 func TestSliceEqual(t *testing.T) {
 	a := SliceOf([]string{"foo", "bar"})
 	b := SliceOf([]string{"foo", "bar"})
@@ -153,6 +330,136 @@ func TestSliceEqual(t *testing.T) {
 	}
 }
 
+func TestSliceFind(t *testing.T) {
+	big := func(x int) bool { return x > 10 }
+	tests := []struct {
+		name   string
+		in     []int
+		want   int
+		wantOK bool
+	}{
+		{"found_first", []int{1, 20, 30}, 20, true},
+		{"not_found", []int{1, 2, 3}, 0, false},
+		{"empty", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SliceFind(SliceOf(tt.in), big)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("SliceFind = %d, %v; want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSliceEqualFunc(t *testing.T) {
+	type pt struct{ x, y int }
+	sameX := func(a, b pt) bool { return a.x == b.x }
+	tests := []struct {
+		name string
+		a, b []pt
+		want bool
+	}{
+		{"nil", nil, nil, true},
+		{"equal", []pt{{1, 2}, {3, 4}}, []pt{{1, 20}, {3, 40}}, true},
+		{"length_mismatch", []pt{{1, 2}}, []pt{{1, 2}, {3, 4}}, false},
+		{"element_mismatch", []pt{{1, 2}, {3, 4}}, []pt{{1, 2}, {5, 4}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SliceEqualFunc(SliceOf(tt.a), SliceOf(tt.b), sameX); got != tt.want {
+				t.Errorf("SliceEqualFunc = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSliceDiffIndex(t *testing.T) {
+	tests := []struct {
+		a, b []int
+		want int
+	}{
+		{nil, nil, -1},
+		{nil, []int{}, -1},
+		{[]int{1, 2, 3}, []int{1, 2, 3}, -1},
+		{[]int{1, 2}, []int{1, 2, 3}, 2},
+		{[]int{1, 2, 3}, []int{1}, 1},
+		{nil, []int{1}, 0},
+		{[]int{1, 2, 3}, []int{1, 5, 3}, 1},
+		{[]int{0, 2}, []int{1, 2}, 0},
+	}
+	for _, tt := range tests {
+		if got := SliceDiffIndex(SliceOf(tt.a), SliceOf(tt.b)); got != tt.want {
+			t.Errorf("SliceDiffIndex(%v, %v) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSliceHasPrefix(t *testing.T) {
+	v := SliceOf([]string{"foo", "bar", "baz"})
+	tests := []struct {
+		prefix []string
+		want   bool
+	}{
+		{nil, true},
+		{[]string{"foo"}, true},
+		{[]string{"foo", "bar", "baz"}, true},
+		{[]string{"bar"}, false},
+		{[]string{"foo", "baz"}, false},
+		{[]string{"foo", "bar", "baz", "qux"}, false},
+	}
+	for _, tt := range tests {
+		if got := SliceHasPrefix(v, SliceOf(tt.prefix)); got != tt.want {
+			t.Errorf("SliceHasPrefix(%v, %q) = %v, want %v", v, tt.prefix, got, tt.want)
+		}
+	}
+
+	b := ByteSliceOf([]byte("\x00\x01hello"))
+	if !b.HasPrefixView(ByteSliceOf([]byte("\x00\x01"))) {
+		t.Error("HasPrefixView(header) = false, want true")
+	}
+	if b.HasPrefixView(ByteSliceOf([]byte("\x00\x02"))) {
+		t.Error("HasPrefixView(other header) = true, want false")
+	}
+	if b.SliceTo(1).HasPrefixView(ByteSliceOf([]byte("\x00\x01"))) {
+		t.Error("HasPrefixView(longer prefix) = true, want false")
+	}
+}
+
+func TestSliceMapKeyEqual(t *testing.T) {
+	backing := []int{1, 2, 3, 4}
+	full := SliceOf(backing)
+	tests := []struct {
+		name string
+		a, b Slice[int]
+		want bool
+	}{
+		{"nil", SliceOf[int](nil), SliceOf[int](nil), true},
+		{"nil_vs_empty", SliceOf[int](nil), SliceOf([]int{}), false},
+		{"empty_different_arrays", SliceOf([]int{}), full.Slice(2, 2), true},
+		{"full", full, SliceOf(backing), true},
+		{"same_contents_different_arrays", full, SliceOf([]int{1, 2, 3, 4}), false},
+		{"different_capacity", SliceOf(backing[:2]), SliceOf(backing[:2:2]), true},
+		{"resliced_differently", full.SliceFrom(1).SliceTo(2), full.Slice(1, 3), true},
+		{"overlapping_different_start", full.Slice(0, 2), full.Slice(1, 3), false},
+		{"overlapping_different_length", full.Slice(1, 2), full.Slice(1, 3), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ka, kb := tt.a.MapKey(), tt.b.MapKey()
+			if got := ka.Equal(kb); got != tt.want {
+				t.Errorf("Equal = %v; want %v", got, tt.want)
+			}
+			if got := kb.Equal(ka); got != tt.want {
+				t.Errorf("reversed Equal = %v; want %v", got, tt.want)
+			}
+			if got := ka == kb; got != tt.want {
+				t.Errorf("== is %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestSliceMapKey tests that the MapKey method returns the same key for slices
 // with the same underlying slice and different keys for different slices or
 // with same underlying slice but different bounds.
@@ -189,3 +496,1194 @@ func TestSliceMapKey(t *testing.T) {
 		}
 	}
 }
+
+// testStruct is a minimal ViewCloner for testing SliceView.
+type testStruct struct {
+	N int
+}
+
+func (s *testStruct) View() testStructView { return testStructView{s} }
+
+func (s *testStruct) Clone() *testStruct {
+	if s == nil {
+		return nil
+	}
+	return &testStruct{s.N}
+}
+
+type testStructView struct {
+	ж *testStruct
+}
+
+func (v testStructView) Valid() bool           { return v.ж != nil }
+func (v testStructView) AsStruct() *testStruct { return v.ж.Clone() }
+
+func TestSliceFilter(t *testing.T) {
+	even := func(x int) bool { return x%2 == 0 }
+	tests := []struct {
+		name string
+		in   []int
+		want []int
+	}{
+		{"nil", nil, nil},
+		{"empty", []int{}, nil},
+		{"some", []int{1, 2, 3, 4}, []int{2, 4}},
+		{"all", []int{2, 4, 6}, []int{2, 4, 6}},
+		{"none", []int{1, 3}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SliceOf(tt.in).Filter(even)
+			if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("Filter(%v) = %#v; want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	in := []int{2, 4}
+	got := SliceOf(in).Filter(even)
+	got[0] = 100
+	if in[0] != 2 {
+		t.Error("mutating Filter result modified the underlying slice")
+	}
+}
+
+func TestSliceCountFuncPartition(t *testing.T) {
+	even := func(x int) bool { return x%2 == 0 }
+	tests := []struct {
+		name      string
+		in        []int
+		wantMatch []int
+		wantRest  []int
+	}{
+		{"nil", nil, nil, nil},
+		{"empty", []int{}, nil, nil},
+		{"mixed", []int{1, 2, 3, 4, 5}, []int{2, 4}, []int{1, 3, 5}},
+		{"all", []int{2, 4}, []int{2, 4}, nil},
+		{"none", []int{3, 1}, nil, []int{3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := SliceOf(tt.in)
+			if got, want := v.CountFunc(even), len(tt.wantMatch); got != want {
+				t.Errorf("CountFunc = %d; want %d", got, want)
+			}
+			match, rest := v.Partition(even)
+			if !slices.Equal(match, tt.wantMatch) || (match == nil) != (tt.wantMatch == nil) {
+				t.Errorf("Partition match = %#v; want %#v", match, tt.wantMatch)
+			}
+			if !slices.Equal(rest, tt.wantRest) || (rest == nil) != (tt.wantRest == nil) {
+				t.Errorf("Partition rest = %#v; want %#v", rest, tt.wantRest)
+			}
+		})
+	}
+
+	in := []int{1, 2}
+	match, rest := SliceOf(in).Partition(even)
+	match[0], rest[0] = 100, 100
+	if !slices.Equal(in, []int{1, 2}) {
+		t.Error("mutating Partition results modified the underlying slice")
+	}
+}
+
+func TestSliceIndexFuncFrom(t *testing.T) {
+	v := SliceOf([]int{1, 2, 3, 4, 5, 6})
+	even := func(x int) bool { return x%2 == 0 }
+	tests := []struct {
+		start int
+		want  int
+	}{
+		{-1, 1},
+		{0, 1},
+		{1, 1},
+		{2, 3},
+		{5, 5},
+		{6, -1},
+		{10, -1},
+	}
+	for _, tt := range tests {
+		if got := v.IndexFuncFrom(tt.start, even); got != tt.want {
+			t.Errorf("IndexFuncFrom(%d) = %d; want %d", tt.start, got, tt.want)
+		}
+	}
+
+	var all []int
+	for i := v.IndexFuncFrom(0, even); i >= 0; i = v.IndexFuncFrom(i+1, even) {
+		all = append(all, i)
+	}
+	if want := []int{1, 3, 5}; !slices.Equal(all, want) {
+		t.Errorf("scanning for all matches: got %v; want %v", all, want)
+	}
+}
+
+func TestSliceHashKey(t *testing.T) {
+	hashString := func(s string, h *maphash.Hash) {
+		h.WriteString(s)
+		h.WriteByte(0)
+	}
+	key := func(s ...string) uint64 {
+		return SliceHashKey(SliceOf(s), hashString)
+	}
+
+	a := []string{"foo", "bar"}
+	b := slices.Clone(a)
+	if &a[0] == &b[0] {
+		t.Fatal("test slices share a backing array")
+	}
+	if ka, kb := key(a...), key(b...); ka != kb {
+		t.Errorf("equal contents: got keys %x and %x", ka, kb)
+	}
+	if key(a...) == key("bar", "foo") {
+		t.Error("different order: got equal keys")
+	}
+	if key(a...) == key("foo") {
+		t.Error("prefix: got equal keys")
+	}
+	if key("foobar") == key("foo", "bar") {
+		t.Error("different split: got equal keys")
+	}
+	if key() != key() {
+		t.Error("empty: got different keys")
+	}
+}
+
+func TestUnmarshalSliceViewValidated(t *testing.T) {
+	errNegative := errors.New("negative N")
+	validate := func(v testStructView) error {
+		if !v.Valid() {
+			return errors.New("null element")
+		}
+		if v.ж.N < 0 {
+			return errNegative
+		}
+		return nil
+	}
+
+	var ok SliceView[*testStruct, testStructView]
+	if err := UnmarshalSliceViewValidated([]byte(`[{"N":1},{"N":2}]`), &ok, validate); err != nil {
+		t.Fatal(err)
+	}
+	if ok.Len() != 2 || ok.At(1).AsStruct().N != 2 {
+		t.Errorf("decoded %v; want [{1} {2}]", ok.AsSlice())
+	}
+
+	var bad SliceView[*testStruct, testStructView]
+	err := UnmarshalSliceViewValidated([]byte(`[{"N":1},{"N":-2},{"N":-3}]`), &bad, validate)
+	if !errors.Is(err, errNegative) || !strings.Contains(err.Error(), "element 1") {
+		t.Errorf("got error %v; want element 1: %v", err, errNegative)
+	}
+	if !bad.IsNil() {
+		t.Errorf("dst modified on validation failure: %v", bad.AsSlice())
+	}
+
+	if err := UnmarshalSliceViewValidated([]byte(`[null]`), &bad, validate); err == nil || !strings.Contains(err.Error(), "null element") {
+		t.Errorf("null element: got error %v", err)
+	}
+	if err := UnmarshalSliceViewValidated([]byte(`[{"N":3}]`), &ok, validate); err == nil {
+		t.Error("decoding into an initialized view succeeded")
+	}
+}
+
+func TestMarshalSliceViewProjected(t *testing.T) {
+	v := SliceOfViews([]*testStruct{{1}, {2}, {3}})
+
+	b, err := MarshalSliceViewProjected(v, func(e testStructView) any {
+		return e.AsStruct().N
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `[1,2,3]`; got != want {
+		t.Errorf("scalar projection = %s; want %s", got, want)
+	}
+
+	type reduced struct {
+		ID  int
+		Odd bool `json:"odd"`
+	}
+	b, err = MarshalSliceViewProjected(v, func(e testStructView) any {
+		n := e.AsStruct().N
+		return reduced{ID: n, Odd: n%2 == 1}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `[{"ID":1,"odd":true},{"ID":2,"odd":false},{"ID":3,"odd":true}]`; got != want {
+		t.Errorf("struct projection = %s; want %s", got, want)
+	}
+
+	for _, tt := range []struct {
+		v    SliceView[*testStruct, testStructView]
+		want string
+	}{
+		{SliceView[*testStruct, testStructView]{}, "null"},
+		{SliceOfViews([]*testStruct{}), "[]"},
+	} {
+		b, err := MarshalSliceViewProjected(tt.v, func(e testStructView) any { return e.AsStruct().N })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("got %s; want %s", b, tt.want)
+		}
+	}
+
+	if _, err := MarshalSliceViewProjected(v, func(testStructView) any { return func() {} }); err == nil {
+		t.Error("projection to unmarshalable value succeeded")
+	}
+}
+
+func TestSliceForEach(t *testing.T) {
+	v := SliceOf([]int{10, 20, 30})
+	var got []int
+	v.ForEach(func(i, e int) bool {
+		if e != (i+1)*10 {
+			t.Errorf("index %d has value %d", i, e)
+		}
+		got = append(got, e)
+		return true
+	})
+	if !slices.Equal(got, []int{10, 20, 30}) {
+		t.Errorf("full iteration: got %v", got)
+	}
+
+	got = nil
+	v.ForEach(func(i, e int) bool {
+		got = append(got, e)
+		return i < 1
+	})
+	if !slices.Equal(got, []int{10, 20}) {
+		t.Errorf("early stop: got %v; want [10 20]", got)
+	}
+
+	sv := SliceOfViews([]*testStruct{{1}, {2}, {3}})
+	got = nil
+	sv.ForEach(func(i int, e testStructView) bool {
+		got = append(got, e.AsStruct().N)
+		return true
+	})
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("SliceView full iteration: got %v", got)
+	}
+	got = nil
+	sv.ForEach(func(i int, e testStructView) bool {
+		got = append(got, e.AsStruct().N)
+		return false
+	})
+	if !slices.Equal(got, []int{1}) {
+		t.Errorf("SliceView early stop: got %v; want [1]", got)
+	}
+}
+
+func TestAppendSliceViewFunc(t *testing.T) {
+	v := SliceOfViews([]*testStruct{{1}, {2}, {3}})
+	name := func(v testStructView) string { return "n" + strconv.Itoa(v.AsStruct().N) }
+
+	got := AppendSliceViewFunc([]string{"start"}, v, name)
+	if want := []string{"start", "n1", "n2", "n3"}; !slices.Equal(got, want) {
+		t.Errorf("AppendSliceViewFunc = %q; want %q", got, want)
+	}
+	if got := AppendSliceViewFunc(nil, SliceOfViews[*testStruct, testStructView](nil), name); got != nil {
+		t.Errorf("AppendSliceViewFunc of nil view = %q; want nil", got)
+	}
+}
+
+func TestSliceViewOf(t *testing.T) {
+	x := []*testStruct{{1}, {2}}
+	a, b := SliceViewOf(x), SliceOfViews(x)
+	if a.MapKey() != b.MapKey() {
+		t.Errorf("SliceViewOf = %v; want %v", a.AsSlice(), b.AsSlice())
+	}
+	if !SliceViewOf[*testStruct, testStructView](nil).IsNil() {
+		t.Error("SliceViewOf(nil) is not nil")
+	}
+
+	// The zero value of each view type is the nil view.
+	var zs SliceView[*testStruct, testStructView]
+	if !zs.IsNil() || zs.MapKey() != SliceOfViews[*testStruct, testStructView](nil).MapKey() {
+		t.Error("zero SliceView != SliceOfViews(nil)")
+	}
+	var zsl Slice[int]
+	if !zsl.IsNil() || zsl.MapKey() != SliceOf[int](nil).MapKey() {
+		t.Error("zero Slice != SliceOf(nil)")
+	}
+	var zb ByteSlice[[]byte]
+	if !zb.IsNil() || zb.MapKey() != ByteSliceOf[[]byte](nil).MapKey() {
+		t.Error("zero ByteSlice != ByteSliceOf(nil)")
+	}
+	var zm Map[string, int]
+	if !zm.IsNil() || zm.MapKey() != MapOf[string, int](nil).MapKey() {
+		t.Error("zero Map != MapOf(nil)")
+	}
+}
+
+func TestSliceViewIndexBy(t *testing.T) {
+	first, second := &testStruct{12}, &testStruct{32}
+	v := SliceOfViews([]*testStruct{first, {5}, second})
+	lastDigit := func(v testStructView) int { return v.ж.N % 10 }
+
+	got := SliceViewIndexBy(v, lastDigit)
+	if len(got) != 2 {
+		t.Fatalf("len = %d; want 2", len(got))
+	}
+	if got[2].ж != second {
+		t.Errorf("got[2] = %v; want last occurrence %v", got[2].ж, second)
+	}
+	if got[5].ж.N != 5 {
+		t.Errorf("got[5] = %v; want {5}", got[5].ж)
+	}
+	if got := SliceViewIndexBy(SliceOfViews[*testStruct, testStructView](nil), lastDigit); got != nil {
+		t.Errorf("SliceViewIndexBy(nil) = %v; want nil", got)
+	}
+}
+
+func TestSliceViewsEqual(t *testing.T) {
+	of := func(ns ...int) SliceView[*testStruct, testStructView] {
+		var x []*testStruct
+		for _, n := range ns {
+			x = append(x, &testStruct{n})
+		}
+		return SliceOfViews(x)
+	}
+	sameN := func(a, b testStructView) bool { return a.ж.N == b.ж.N }
+	tests := []struct {
+		name string
+		a, b SliceView[*testStruct, testStructView]
+		want bool
+	}{
+		{"both_nil", of(), of(), true},
+		{"equal", of(1, 2, 3), of(1, 2, 3), true},
+		{"different_element", of(1, 2, 3), of(1, 5, 3), false},
+		{"different_length", of(1, 2), of(1, 2, 3), false},
+		{"nil_vs_empty", of(), SliceOfViews([]*testStruct{}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SliceViewsEqual(tt.a, tt.b, sameN); got != tt.want {
+				t.Errorf("SliceViewsEqual = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSliceConcat(t *testing.T) {
+	tests := []struct {
+		name string
+		in   [][]int
+		want []int
+	}{
+		{"no_inputs", nil, nil},
+		{"all_nil", [][]int{nil, nil}, nil},
+		{"all_empty", [][]int{{}, {}}, nil},
+		{"one", [][]int{{1, 2}}, []int{1, 2}},
+		{"mixed", [][]int{nil, {1}, {}, {2, 3}, nil}, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var vs []Slice[int]
+			for _, x := range tt.in {
+				vs = append(vs, SliceOf(x))
+			}
+			got := SliceConcat(vs...)
+			if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("SliceConcat = %#v; want %#v", got, tt.want)
+			}
+			if cap(got) != len(got) {
+				t.Errorf("cap = %d; want %d", cap(got), len(got))
+			}
+		})
+	}
+
+	in := []int{1, 2}
+	got := SliceConcat(SliceOf(in))
+	got[0] = 100
+	if in[0] != 1 {
+		t.Error("mutating SliceConcat result modified an input")
+	}
+}
+
+func TestSliceViewIndexFunc(t *testing.T) {
+	v := SliceOfViews([]*testStruct{{1}, nil, {3}, {4}})
+	var calls int
+	isN := func(n int) func(testStructView) bool {
+		return func(e testStructView) bool {
+			calls++
+			return e.Valid() && e.AsStruct().N == n
+		}
+	}
+
+	if got := v.IndexFunc(isN(3)); got != 2 {
+		t.Errorf("IndexFunc(N == 3) = %d; want 2", got)
+	}
+	if got := v.IndexFunc(isN(5)); got != -1 {
+		t.Errorf("IndexFunc(N == 5) = %d; want -1", got)
+	}
+	if got := v.IndexFunc(func(e testStructView) bool { return !e.Valid() }); got != 1 {
+		t.Errorf("IndexFunc(!Valid) = %d; want 1", got)
+	}
+
+	calls = 0
+	if !v.ContainsFunc(isN(1)) {
+		t.Error("ContainsFunc(N == 1) = false; want true")
+	}
+	if calls != 1 {
+		t.Errorf("ContainsFunc called predicate %d times; want 1", calls)
+	}
+	if v.ContainsFunc(isN(5)) {
+		t.Error("ContainsFunc(N == 5) = true; want false")
+	}
+}
+
+func TestSliceUniq(t *testing.T) {
+	in := []int{3, 1, 3, 2, 1, 1, 4, 2}
+	got := SliceUniq(SliceOf(in))
+	if want := []int{3, 1, 2, 4}; !slices.Equal(got.AsSlice(), want) {
+		t.Errorf("SliceUniq = %v; want %v", got.AsSlice(), want)
+	}
+	if !slices.Equal(in, []int{3, 1, 3, 2, 1, 1, 4, 2}) {
+		t.Errorf("SliceUniq modified its input: %v", in)
+	}
+	if got := SliceUniq(SliceOf[int](nil)); !got.IsNil() {
+		t.Errorf("SliceUniq(nil) = %v; want nil", got.AsSlice())
+	}
+
+	for _, tt := range []struct {
+		name string
+		in   []int
+		want []int
+	}{
+		{"all_unique", []int{4, 2, 3}, []int{4, 2, 3}},
+		{"all_duplicate", []int{7, 7, 7}, []int{7}},
+		{"interleaved", []int{1, 2, 1, 2, 3, 1}, []int{1, 2, 3}},
+	} {
+		if got := SliceUniq(SliceOf(tt.in)).AsSlice(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: SliceUniq = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+
+	words := []string{"Foo", "bar", "FOO", "Bar", "baz", "foo"}
+	got2 := SliceUniqFunc(SliceOf(words), strings.ToLower)
+	if want := []string{"Foo", "bar", "baz"}; !slices.Equal(got2.AsSlice(), want) {
+		t.Errorf("SliceUniqFunc = %q; want %q", got2.AsSlice(), want)
+	}
+}
+
+func TestMapMarshalJSONStable(t *testing.T) {
+	src := map[int]string{}
+	for i := range 100 {
+		src[i*7%101] = strconv.Itoa(i)
+	}
+	m := MapOf(src)
+	want, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		got, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("Map[int,string] marshaled differently:\n%s\n%s", got, want)
+		}
+	}
+
+	got, err := json.Marshal(MapOf(map[int]string{10: "a", 2: "b", 1: "c"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"1":"c","10":"a","2":"b"}`; string(got) != want {
+		t.Errorf("got %s; want %s", got, want)
+	}
+}
+
+func TestMapFilter(t *testing.T) {
+	even := func(_ string, v int) bool { return v%2 == 0 }
+	src := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	got := MapOf(src).Filter(even)
+	if want := map[string]int{"b": 2, "d": 4}; !maps.Equal(got, want) {
+		t.Errorf("Filter = %v; want %v", got, want)
+	}
+	got["b"] = 100
+	if src["b"] != 2 {
+		t.Error("mutating Filter result modified the underlying map")
+	}
+
+	if got := MapOf(map[string]int{"a": 1}).Filter(even); got == nil || len(got) != 0 {
+		t.Errorf("Filter with nothing kept = %#v; want empty non-nil map", got)
+	}
+	if got := MapOf[string, int](nil).Filter(even); got != nil {
+		t.Errorf("Filter of nil map = %#v; want nil", got)
+	}
+}
+
+func TestMapCloneDeep(t *testing.T) {
+	src := map[string]*testStruct{"a": {1}, "b": {2}}
+	m := MapOf(src)
+	got := MapCloneDeep(m)
+	if len(got) != 2 || got["a"].N != 1 || got["b"].N != 2 {
+		t.Fatalf("MapCloneDeep = %v; want a:1, b:2", got)
+	}
+	got["a"].N = 100
+	delete(got, "b")
+	if src["a"].N != 1 || len(src) != 2 {
+		t.Errorf("mutating clone affected source: a.N = %d, len = %d", src["a"].N, len(src))
+	}
+	if got := MapCloneDeep(MapOf[string, *testStruct](nil)); got != nil {
+		t.Errorf("MapCloneDeep of nil map = %v; want nil", got)
+	}
+}
+
+func TestMapMerge(t *testing.T) {
+	defaults := MapOf(map[string]int{"a": 1, "b": 2})
+	overrides := MapOf(map[string]int{"b": 20, "c": 30})
+	last := MapOf(map[string]int{"c": 300})
+
+	got := MapMerge(nil, defaults, MapOf[string, int](nil), overrides, last)
+	if want := map[string]int{"a": 1, "b": 20, "c": 300}; !maps.Equal(got, want) {
+		t.Errorf("MapMerge = %v; want %v", got, want)
+	}
+
+	dst := map[string]int{"a": 100, "z": 26}
+	got = MapMerge(dst, defaults)
+	if want := map[string]int{"a": 1, "b": 2, "z": 26}; !maps.Equal(got, want) {
+		t.Errorf("MapMerge into dst = %v; want %v", got, want)
+	}
+	got["x"] = 1
+	if _, ok := dst["x"]; !ok {
+		t.Error("MapMerge did not return dst")
+	}
+	if defaults.Len() != 2 {
+		t.Error("MapMerge modified a source view")
+	}
+
+	if got := MapMerge[string, int](nil); got == nil || len(got) != 0 {
+		t.Errorf("MapMerge(nil) = %#v; want empty non-nil map", got)
+	}
+}
+
+func TestResetAndUnmarshalJSON(t *testing.T) {
+	first := []int{1, 2}
+	s := SliceOf(first)
+	if err := json.Unmarshal([]byte(`[3]`), &s); err == nil {
+		t.Error("UnmarshalJSON into initialized Slice succeeded")
+	}
+	if err := s.ResetAndUnmarshalJSON([]byte(`[3,4,5]`)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.AsSlice(), []int{3, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("Slice = %v; want %v", got, want)
+	}
+	if !slices.Equal(first, []int{1, 2}) {
+		t.Errorf("previous underlying slice modified: %v", first)
+	}
+
+	sv := SliceOfViews([]*testStruct{{1}})
+	if err := sv.ResetAndUnmarshalJSON([]byte(`[{"N":2},{"N":3}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if sv.Len() != 2 || sv.At(0).AsStruct().N != 2 || sv.At(1).AsStruct().N != 3 {
+		t.Errorf("SliceView = %v; want [{2} {3}]", sv.AsSlice())
+	}
+
+	firstMap := map[string]int{"a": 1}
+	m := MapOf(firstMap)
+	if err := m.ResetAndUnmarshalJSON([]byte(`{"b":2}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.AsMap(), map[string]int{"b": 2}; !maps.Equal(got, want) {
+		t.Errorf("Map = %v; want %v", got, want)
+	}
+	if want := map[string]int{"a": 1}; !maps.Equal(firstMap, want) {
+		t.Errorf("previous underlying map modified: %v", firstMap)
+	}
+}
+
+func TestMapRangeErr(t *testing.T) {
+	m := MapOf(map[string]int{"a": 1, "b": 2, "c": 3})
+
+	seen := map[string]int{}
+	err := m.RangeErr(func(k string, v int) error {
+		seen[k] = v
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RangeErr = %v; want nil", err)
+	}
+	if !maps.Equal(seen, m.AsMap()) {
+		t.Errorf("visited %v; want %v", seen, m.AsMap())
+	}
+
+	errStop := errors.New("stop")
+	var calls int
+	err = m.RangeErr(func(k string, v int) error {
+		calls++
+		return errStop
+	})
+	if err != errStop {
+		t.Errorf("RangeErr = %v; want %v", err, errStop)
+	}
+	if calls != 1 {
+		t.Errorf("f called %d times after error; want 1", calls)
+	}
+
+	if err := MapOf[string, int](nil).RangeErr(func(string, int) error { return errStop }); err != nil {
+		t.Errorf("RangeErr on nil map = %v; want nil", err)
+	}
+}
+
+func TestMapGetOr(t *testing.T) {
+	m := MapOf(map[string]int{"a": 1, "zero": 0})
+	if got := m.GetOr("a", 5); got != 1 {
+		t.Errorf("GetOr(present) = %d; want 1", got)
+	}
+	if got := m.GetOr("zero", 5); got != 0 {
+		t.Errorf("GetOr(present zero value) = %d; want 0", got)
+	}
+	if got := m.GetOr("missing", 5); got != 5 {
+		t.Errorf("GetOr(absent) = %d; want 5", got)
+	}
+	if got := MapOf[string, int](nil).GetOr("a", 5); got != 5 {
+		t.Errorf("GetOr on nil map = %d; want 5", got)
+	}
+}
+
+func TestMapCloneView(t *testing.T) {
+	src := map[string]int{"a": 1, "b": 2}
+	m := MapOf(src)
+	c := m.CloneView()
+
+	src["a"] = 10
+	src["c"] = 3
+	delete(src, "b")
+
+	if got, want := c.AsMap(), map[string]int{"a": 1, "b": 2}; !maps.Equal(got, want) {
+		t.Errorf("CloneView after mutating source = %v; want %v", got, want)
+	}
+	if got := m.Get("a"); got != 10 {
+		t.Errorf("original view Get(a) = %d; want 10", got)
+	}
+
+	if c := MapOf[string, int](nil).CloneView(); !c.IsNil() {
+		t.Errorf("CloneView of nil map = %v; want nil", c)
+	}
+}
+
+func TestMapSum(t *testing.T) {
+	a := MapOf(map[string]int{"x": 1, "y": 2})
+	b := MapOf(map[string]int{"y": 10, "z": 20}) // overlaps a on y
+	c := MapOf(map[string]int{"w": 100})         // disjoint
+
+	got := MapSum(a, b, c).AsMap()
+	want := map[string]int{"w": 100, "x": 1, "y": 12, "z": 20}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapSum = %v; want %v", got, want)
+	}
+	if a.Get("y") != 2 || b.Get("y") != 10 {
+		t.Error("MapSum modified its inputs")
+	}
+	if got := MapSum[string, int](); got.Len() != 0 {
+		t.Errorf("MapSum() = %v; want empty", got.AsMap())
+	}
+
+	dst := map[string]float64{"x": 0.5}
+	MapSumInto(dst, MapOf(map[string]float64{"x": 0.25, "y": 1}))
+	if want := map[string]float64{"x": 0.75, "y": 1}; !reflect.DeepEqual(dst, want) {
+		t.Errorf("MapSumInto = %v; want %v", dst, want)
+	}
+}
+
+func TestMapViewMapKey(t *testing.T) {
+	underlying := map[string]int{"foo": 1}
+	m1 := MapOf(underlying)
+	m2 := MapOf(underlying)
+	if m1.MapKey() != m2.MapKey() {
+		t.Error("views over the same map have different keys")
+	}
+
+	underlying["bar"] = 2 // mutating the map doesn't change its identity
+	if m1.MapKey() != m2.MapKey() {
+		t.Error("keys differ after mutation")
+	}
+
+	wantDiff := []Map[string, int]{
+		MapOf[string, int](nil),
+		MapOf(map[string]int{}),
+		m1,
+		MapOf(map[string]int{"foo": 1, "bar": 2}), // same contents, different map
+	}
+	for i := range len(wantDiff) {
+		for j := i + 1; j < len(wantDiff); j++ {
+			if wantDiff[i].MapKey() == wantDiff[j].MapKey() {
+				t.Errorf("wantDiff[%d] and wantDiff[%d] have equal keys", i, j)
+			}
+		}
+	}
+	if MapOf[string, int](nil).MapKey() != MapOf[string, int](nil).MapKey() {
+		t.Error("nil maps have different keys")
+	}
+}
+
+func TestDecodeSliceStream(t *testing.T) {
+	const n = 10000
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := range n {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"Int":%d}`, i)
+	}
+	sb.WriteString("]")
+
+	var got int
+	err := DecodeSliceStream(strings.NewReader(sb.String()), func(v viewStruct) error {
+		if v.Int != got {
+			return fmt.Errorf("got element %d; want %d", v.Int, got)
+		}
+		got++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != n {
+		t.Errorf("got %d elements; want %d", got, n)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = DecodeSliceStream(strings.NewReader(`[1,2,3]`), func(int) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("got (%v, %d calls); want (%v, 1 call)", err, calls, errStop)
+	}
+
+	if err := DecodeSliceStream(strings.NewReader(`null`), func(int) error {
+		t.Error("unexpected call for null")
+		return nil
+	}); err != nil {
+		t.Errorf("null: %v", err)
+	}
+	if err := DecodeSliceStream(strings.NewReader(`{"a":1}`), func(int) error { return nil }); err == nil {
+		t.Error("object: got nil error; want non-nil")
+	}
+}
+
+func TestSliceMarshalBinary(t *testing.T) {
+	addrs := []netip.Addr{
+		netip.MustParseAddr("100.64.0.1"),
+		netip.MustParseAddr("fd7a:115c:a1e0::1"),
+		{}, // zero Addr marshals to an empty element
+	}
+	b, err := SliceMarshalBinary(SliceOf(addrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeSliceBinary[netip.Addr](b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.AsSlice(), addrs) {
+		t.Errorf("round trip = %v; want %v", got.AsSlice(), addrs)
+	}
+
+	if nb, err := SliceMarshalBinary(SliceOf[netip.Addr](nil)); err != nil || len(nb) != 0 {
+		t.Errorf("nil slice = (%q, %v); want empty", nb, err)
+	}
+	if _, err := DecodeSliceBinary[netip.Addr](b[:3]); err == nil {
+		t.Error("truncated input: got nil error")
+	}
+}
+
+func TestSliceSumAvg(t *testing.T) {
+	ints := SliceOf([]int64{1, 2, 3, 4})
+	if got := SliceSum(ints); got != 10 {
+		t.Errorf("SliceSum(ints) = %v; want 10", got)
+	}
+	if got := SliceAvg(ints); got != 2.5 {
+		t.Errorf("SliceAvg(ints) = %v; want 2.5", got)
+	}
+
+	floats := SliceOf([]float64{0.5, 1.5, 4})
+	if got := SliceSum(floats); got != 6 {
+		t.Errorf("SliceSum(floats) = %v; want 6", got)
+	}
+	if got := SliceAvg(floats); got != 2 {
+		t.Errorf("SliceAvg(floats) = %v; want 2", got)
+	}
+
+	var empty Slice[uint8]
+	if got := SliceSum(empty); got != 0 {
+		t.Errorf("SliceSum(empty) = %v; want 0", got)
+	}
+	if got := SliceAvg(empty); got != 0 {
+		t.Errorf("SliceAvg(empty) = %v; want 0", got)
+	}
+}
+
+func TestSliceIsSorted(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		want bool
+	}{
+		{"nil", nil, true},
+		{"single", []int{5}, true},
+		{"sorted", []int{1, 2, 5, 9}, true},
+		{"equal_elements", []int{3, 3, 3}, true},
+		{"sorted_with_ties", []int{1, 2, 2, 4}, true},
+		{"unsorted", []int{1, 3, 2}, false},
+		{"descending", []int{3, 2, 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := SliceOf(tt.in)
+			if got := SliceIsSorted(v); got != tt.want {
+				t.Errorf("SliceIsSorted = %v; want %v", got, tt.want)
+			}
+			if got := SliceIsSortedFunc(v, cmp.Compare[int]); got != tt.want {
+				t.Errorf("SliceIsSortedFunc = %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	desc := func(a, b int) int { return cmp.Compare(b, a) }
+	if !SliceIsSortedFunc(SliceOf([]int{3, 2, 2, 1}), desc) {
+		t.Error("SliceIsSortedFunc(descending) = false; want true")
+	}
+}
+
+func TestSliceBinarySearch(t *testing.T) {
+	sorted := []int{1, 3, 3, 5, 8, 13}
+	v := SliceOf(sorted)
+	byAbs := func(a, b int) int { return cmp.Compare(a*a, b*b) }
+	for _, target := range []int{0, 1, 2, 3, 5, 13, 14} {
+		gotI, gotOK := SliceBinarySearch(v, target)
+		wantI, wantOK := slices.BinarySearch(sorted, target)
+		if gotI != wantI || gotOK != wantOK {
+			t.Errorf("SliceBinarySearch(%d) = %d, %v; want %d, %v", target, gotI, gotOK, wantI, wantOK)
+		}
+		gotI, gotOK = SliceBinarySearchFunc(v, target, byAbs)
+		wantI, wantOK = slices.BinarySearchFunc(sorted, target, byAbs)
+		if gotI != wantI || gotOK != wantOK {
+			t.Errorf("SliceBinarySearchFunc(%d) = %d, %v; want %d, %v", target, gotI, gotOK, wantI, wantOK)
+		}
+	}
+	if i, ok := SliceBinarySearch(SliceOf[int](nil), 1); i != 0 || ok {
+		t.Errorf("nil slice: got %d, %v; want 0, false", i, ok)
+	}
+}
+
+func TestSliceMinMax(t *testing.T) {
+	c := qt.New(t)
+
+	empty := SliceOf[int](nil)
+	_, ok := SliceMax(empty)
+	c.Check(ok, qt.IsFalse)
+	_, ok = SliceMin(empty)
+	c.Check(ok, qt.IsFalse)
+
+	single := SliceOf([]int{7})
+	got, ok := SliceMax(single)
+	c.Check(ok, qt.IsTrue)
+	c.Check(got, qt.Equals, 7)
+	got, ok = SliceMin(single)
+	c.Check(ok, qt.IsTrue)
+	c.Check(got, qt.Equals, 7)
+
+	multi := SliceOf([]int{3, 9, -1, 9, 4})
+	got, _ = SliceMax(multi)
+	c.Check(got, qt.Equals, 9)
+	got, _ = SliceMin(multi)
+	c.Check(got, qt.Equals, -1)
+
+	type kv struct {
+		k string
+		v int
+	}
+	byV := func(a, b kv) int { return cmp.Compare(a.v, b.v) }
+	kvs := SliceOf([]kv{{"a", 2}, {"b", 5}, {"c", 5}, {"d", 1}, {"e", 1}})
+	gotKV, ok := SliceMaxFunc(kvs, byV)
+	c.Check(ok, qt.IsTrue)
+	c.Check(gotKV, qt.Equals, kv{"b", 5}) // first of ties
+	gotKV, ok = SliceMinFunc(kvs, byV)
+	c.Check(ok, qt.IsTrue)
+	c.Check(gotKV, qt.Equals, kv{"d", 1}) // first of ties
+	_, ok = SliceMaxFunc(SliceOf[kv](nil), byV)
+	c.Check(ok, qt.IsFalse)
+	_, ok = SliceMinFunc(SliceOf[kv](nil), byV)
+	c.Check(ok, qt.IsFalse)
+}
+
+func TestMapEqual(t *testing.T) {
+	a := MapOf(map[string]int{"a": 1, "b": 2})
+	tests := []struct {
+		name string
+		a, b Map[string, int]
+		want bool
+	}{
+		{"same", a, a, true},
+		{"equal", a, MapOf(map[string]int{"b": 2, "a": 1}), true},
+		{"different-value", a, MapOf(map[string]int{"a": 1, "b": 3}), false},
+		{"different-key", a, MapOf(map[string]int{"a": 1, "c": 2}), false},
+		{"subset", a, MapOf(map[string]int{"a": 1}), false},
+		{"nil-vs-empty", MapOf[string, int](nil), MapOf(map[string]int{}), true},
+		{"nil-vs-nonempty", MapOf[string, int](nil), a, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MapEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("MapEqual = %v; want %v", got, tt.want)
+			}
+			if got := MapEqual(tt.b, tt.a); got != tt.want {
+				t.Errorf("MapEqual (swapped) = %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Slice values aren't comparable, so need MapEqualFunc.
+	sliceEq := func(x, y []int) bool { return slices.Equal(x, y) }
+	x := Map[string, []int]{map[string][]int{"a": {1, 2}}}
+	y := Map[string, []int]{map[string][]int{"a": {1, 2}}}
+	z := Map[string, []int]{map[string][]int{"a": {2, 1}}}
+	if !MapEqualFunc(x, y, sliceEq) {
+		t.Error("MapEqualFunc(x, y) = false; want true")
+	}
+	if MapEqualFunc(x, z, sliceEq) {
+		t.Error("MapEqualFunc(x, z) = true; want false")
+	}
+}
+
+func TestMapFnAsMap(t *testing.T) {
+	calls := map[string]int{}
+	wrap := func(v []string) Slice[string] {
+		for _, s := range v {
+			calls[s]++
+		}
+		return SliceOf(v)
+	}
+	m := MapFnOf(map[string][]string{"a": {"a"}, "b": {"b"}}, wrap)
+	got := m.AsMap()
+	if len(got) != 2 || !SliceEqual(got["a"], SliceOf([]string{"a"})) || !SliceEqual(got["b"], SliceOf([]string{"b"})) {
+		t.Errorf("AsMap = %v; want map[a:[a] b:[b]]", got)
+	}
+	if want := map[string]int{"a": 1, "b": 1}; !maps.Equal(calls, want) {
+		t.Errorf("wrap calls = %v; want %v", calls, want)
+	}
+	if got := MapFnOf[string](nil, wrap).AsMap(); got != nil {
+		t.Errorf("nil map: AsMap = %v; want nil", got)
+	}
+}
+
+func TestMapFnGetOr(t *testing.T) {
+	var calls int
+	wrap := func(v []string) Slice[string] {
+		calls++
+		return SliceOf(v)
+	}
+	m := MapFnOf(map[string][]string{"a": {"x", "y"}}, wrap)
+	def := SliceOf([]string{"default"})
+
+	if got := m.GetOr("a", def); !SliceEqual(got, SliceOf([]string{"x", "y"})) {
+		t.Errorf("GetOr(present) = %v; want [x y]", got)
+	}
+	if calls != 1 {
+		t.Errorf("wrap called %d times for present key; want 1", calls)
+	}
+
+	calls = 0
+	if got := m.GetOr("missing", def); !SliceEqual(got, def) {
+		t.Errorf("GetOr(absent) = %v; want %v", got, def)
+	}
+	if got := MapFnOf[string](nil, wrap).GetOr("a", def); !SliceEqual(got, def) {
+		t.Errorf("GetOr on nil map = %v; want %v", got, def)
+	}
+	if calls != 0 {
+		t.Errorf("wrap called %d times for absent keys; want 0", calls)
+	}
+}
+
+func TestMapFnFilter(t *testing.T) {
+	calls := map[string]int{}
+	wrap := func(v []string) Slice[string] {
+		calls[v[0]]++
+		return SliceOf(v)
+	}
+	m := MapFnOf(map[string][]string{"a": {"a"}, "bb": {"b", "b"}, "cc": {"c", "c"}}, wrap)
+	long := func(_ string, v Slice[string]) bool { return v.Len() > 1 }
+
+	got := m.Filter(long)
+	if len(got) != 2 || !SliceEqual(got["bb"], SliceOf([]string{"b", "b"})) || !SliceEqual(got["cc"], SliceOf([]string{"c", "c"})) {
+		t.Errorf("Filter = %v; want map[bb:[b b] cc:[c c]]", got)
+	}
+	if want := map[string]int{"a": 1, "b": 1, "c": 1}; !maps.Equal(calls, want) {
+		t.Errorf("wrap calls = %v; want %v", calls, want)
+	}
+
+	if got := m.Filter(func(string, Slice[string]) bool { return false }); got == nil || len(got) != 0 {
+		t.Errorf("Filter with nothing kept = %#v; want empty non-nil map", got)
+	}
+	if got := MapFnOf[string](nil, wrap).Filter(long); got != nil {
+		t.Errorf("Filter of nil map = %#v; want nil", got)
+	}
+}
+
+func TestMapSortedKeys(t *testing.T) {
+	m := MapOf(map[string]int{"c": 3, "a": 1, "b": 2, "z": 26})
+	want := []string{"a", "b", "c", "z"}
+	for range 10 {
+		if got := MapSortedKeys(m); !slices.Equal(got, want) {
+			t.Fatalf("MapSortedKeys = %q; want %q", got, want)
+		}
+	}
+	if got := MapSortedKeys(MapOf[string, int](nil)); got != nil {
+		t.Errorf("nil map: got %q; want nil", got)
+	}
+	if got := MapSortedKeys(MapOf(map[string]int{})); got == nil || len(got) != 0 {
+		t.Errorf("empty map: got %#v; want empty non-nil", got)
+	}
+}
+
+func TestMapEntriesSorted(t *testing.T) {
+	m := MapOf(map[string]int{"c": 3, "a": 1, "b": 2, "z": 26})
+	got := MapEntriesSorted(m)
+	want := []KV[string, int]{{"a", 1}, {"b", 2}, {"c", 3}, {"z", 26}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapEntriesSorted = %v; want %v", got, want)
+	}
+
+	if got := MapEntriesSorted(MapOf[string, int](nil)); got != nil {
+		t.Errorf("nil map: got %v; want nil", got)
+	}
+	if got := MapEntriesSorted(MapOf(map[string]int{})); got != nil {
+		t.Errorf("empty map: got %v; want nil", got)
+	}
+}
+
+func TestMapToSortedSlice(t *testing.T) {
+	type peer struct {
+		Name    string
+		RxBytes int
+	}
+	m := MapOf(map[string]int{"c": 30, "a": 10, "b": 20, "d": 20})
+	toPeer := func(k string, v int) peer { return peer{k, v} }
+
+	byName := MapToSortedSlice(m, toPeer, func(a, b peer) bool { return a.Name < b.Name })
+	want := []peer{{"a", 10}, {"b", 20}, {"c", 30}, {"d", 20}}
+	if got := byName.AsSlice(); !reflect.DeepEqual(got, want) {
+		t.Errorf("by name = %v; want %v", got, want)
+	}
+
+	byRx := MapToSortedSlice(m, toPeer, func(a, b peer) bool {
+		if a.RxBytes != b.RxBytes {
+			return a.RxBytes > b.RxBytes
+		}
+		return a.Name < b.Name
+	})
+	want = []peer{{"c", 30}, {"b", 20}, {"d", 20}, {"a", 10}}
+	if got := byRx.AsSlice(); !reflect.DeepEqual(got, want) {
+		t.Errorf("by rx bytes = %v; want %v", got, want)
+	}
+
+	if got := MapToSortedSlice(MapOf[string, int](nil), toPeer, func(a, b peer) bool { return false }); got.Len() != 0 {
+		t.Errorf("nil map: got %v; want empty", got)
+	}
+}
+
+func TestSliceAppendDelimited(t *testing.T) {
+	tests := []struct {
+		in   []int
+		want string
+	}{
+		{nil, "row:"},
+		{[]int{1}, "row:1"},
+		{[]int{1, 22, 333}, "row:1,22,333"},
+	}
+	for _, tt := range tests {
+		got := SliceOf(tt.in).AppendDelimited([]byte("row:"), ',', strconv.Itoa)
+		if string(got) != tt.want {
+			t.Errorf("AppendDelimited(%v) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+
+	row := SliceOf([]string{"a", "", "c"}).AppendDelimited(nil, '\t', func(s string) string { return s })
+	if string(row) != "a\t\tc" {
+		t.Errorf("with empty field = %q; want %q", row, "a\t\tc")
+	}
+}
+
+func TestSliceJoin(t *testing.T) {
+	c := qt.New(t)
+	addrs := func(ss ...string) Slice[netip.Addr] {
+		var out []netip.Addr
+		for _, s := range ss {
+			out = append(out, netip.MustParseAddr(s))
+		}
+		return SliceOf(out)
+	}
+	c.Check(SliceJoinStrings(addrs(), ", "), qt.Equals, "")
+	c.Check(SliceJoinStrings(addrs("100.64.0.1"), ", "), qt.Equals, "100.64.0.1")
+	c.Check(SliceJoinStrings(addrs("100.64.0.1", "fd7a:115c:a1e0::1"), ", "), qt.Equals, "100.64.0.1, fd7a:115c:a1e0::1")
+
+	quote := func(s string) string { return `"` + s + `"` }
+	c.Check(SliceJoin(SliceOf[string](nil), ",", quote), qt.Equals, "")
+	c.Check(SliceJoin(SliceOf([]string{"a"}), ",", quote), qt.Equals, `"a"`)
+	c.Check(SliceJoin(SliceOf([]string{"a", "b", "c"}), ",", quote), qt.Equals, `"a","b","c"`)
+}
+
+func TestStringsJoin(t *testing.T) {
+	tests := []struct {
+		in   []string
+		sep  string
+		want string
+	}{
+		{nil, ", ", ""},
+		{[]string{}, ", ", ""},
+		{[]string{"a"}, ", ", "a"},
+		{[]string{"a", "b", "c"}, ", ", "a, b, c"},
+		{[]string{"a", "b", "c"}, "", "abc"},
+		{[]string{"", "b", ""}, "-", "-b-"},
+	}
+	for _, tt := range tests {
+		if got := StringsJoin(SliceOf(tt.in), tt.sep); got != tt.want {
+			t.Errorf("StringsJoin(%q, %q) = %q; want %q", tt.in, tt.sep, got, tt.want)
+		}
+	}
+
+	v := SliceOf([]string{"foo", "bar", "baz"})
+	if n := testing.AllocsPerRun(100, func() { StringsJoin(v, ",") }); n != 1 {
+		t.Errorf("StringsJoin allocs = %v; want 1", n)
+	}
+}
+
+func TestSliceClone(t *testing.T) {
+	src := []int{1, 2, 3}
+	v := SliceOf(src)
+	c := v.Clone()
+
+	src[0] = 10
+	if got, want := c.AsSlice(), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("Clone after mutating source = %v; want %v", got, want)
+	}
+	if got := v.At(0); got != 10 {
+		t.Errorf("original view At(0) = %d; want 10", got)
+	}
+
+	if c := SliceOf[int](nil).Clone(); !c.IsNil() {
+		t.Errorf("Clone of nil slice = %v; want nil", c)
+	}
+
+	// Appending to the owner's slice within its capacity must not be
+	// visible through a clone taken before the append.
+	buf := make([]int, 2, 4)
+	buf[0], buf[1] = 1, 2
+	snap := SliceOf(buf).Clone()
+	buf = append(buf, 3)
+	buf[0] = 100
+	if got, want := snap.AsSlice(), []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("Clone after owner append = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package views
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// This file implements YAML marshaling for the view types. It uses the
+// interface-free method signatures understood by gopkg.in/yaml.v3 (and v2)
+// so that this package does not need to import a YAML library.
+//
+// Values are converted to and from YAML by way of their JSON encoding, so
+// that YAML has the same content as JSON. In particular, struct fields are
+// named as in JSON, following json tags, rather than by the YAML library's
+// own rules.
+
+// MarshalYAML implements yaml.Marshaler.
+// Like MarshalJSON, it encodes v as a base64 string.
+func (v ByteSlice[T]) MarshalYAML() (any, error) { return yamlValueFromJSON(v) }
+
+// UnmarshalYAML implements the function form of yaml.Unmarshaler.
+// It should only be called on an uninitialized ByteSlice.
+func (v *ByteSlice[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAMLAsJSON(unmarshal, v.UnmarshalJSON)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (v Slice[T]) MarshalYAML() (any, error) { return yamlValueFromJSON(v) }
+
+// UnmarshalYAML implements the function form of yaml.Unmarshaler.
+// It should only be called on an uninitialized Slice.
+func (v *Slice[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAMLAsJSON(unmarshal, v.UnmarshalJSON)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (v SliceView[T, V]) MarshalYAML() (any, error) { return yamlValueFromJSON(v) }
+
+// UnmarshalYAML implements the function form of yaml.Unmarshaler.
+// It should only be called on an uninitialized SliceView.
+func (v *SliceView[T, V]) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAMLAsJSON(unmarshal, v.UnmarshalJSON)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (m Map[K, V]) MarshalYAML() (any, error) { return yamlValueFromJSON(m) }
+
+// UnmarshalYAML implements the function form of yaml.Unmarshaler.
+// It should only be called on an uninitialized Map.
+func (m *Map[K, V]) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshalYAMLAsJSON(unmarshal, m.UnmarshalJSON)
+}
+
+// yamlValueFromJSON returns the JSON encoding of v decoded into maps,
+// slices and scalars, for a YAML library to encode.
+func yamlValueFromJSON(v json.Marshaler) (any, error) {
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var x any
+	if err := dec.Decode(&x); err != nil {
+		return nil, err
+	}
+	return fromJSONNumbers(x), nil
+}
+
+// fromJSONNumbers returns x with each json.Number replaced by an int64, or
+// a float64 if it isn't an integer, so that YAML encodes it as a number.
+func fromJSONNumbers(x any) any {
+	switch x := x.(type) {
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			return n
+		}
+		if f, err := x.Float64(); err == nil {
+			return f
+		}
+		return x.String()
+	case []any:
+		for i, e := range x {
+			x[i] = fromJSONNumbers(e)
+		}
+	case map[string]any:
+		for k, e := range x {
+			x[k] = fromJSONNumbers(e)
+		}
+	}
+	return x
+}
+
+// unmarshalYAMLAsJSON decodes a YAML value with unmarshal, and passes its
+// JSON encoding to unmarshalJSON.
+func unmarshalYAMLAsJSON(unmarshal func(any) error, unmarshalJSON func([]byte) error) error {
+	var x any
+	if err := unmarshal(&x); err != nil {
+		return err
+	}
+	b, err := json.Marshal(toJSONValue(x))
+	if err != nil {
+		return err
+	}
+	return unmarshalJSON(b)
+}
+
+// toJSONValue returns x, as decoded by a YAML library, with any
+// map[any]any (as decoded by yaml.v2) converted to a map[string]any that
+// encoding/json can encode.
+func toJSONValue(x any) any {
+	switch x := x.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(x))
+		for k, e := range x {
+			m[fmt.Sprint(k)] = toJSONValue(e)
+		}
+		return m
+	case map[string]any:
+		for k, e := range x {
+			x[k] = toJSONValue(e)
+		}
+	case []any:
+		for i, e := range x {
+			x[i] = toJSONValue(e)
+		}
+	}
+	return x
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Tailscale Inc & AUTHORS
+// SPDX-License-Identifier: BSD-3-Clause
+
+package views
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAMLRoundTrip(t *testing.T) {
+	type tagged struct {
+		Name   string  `json:"name"`
+		Weight float64 `json:"weight,omitempty"`
+		Secret string  `json:"-"`
+	}
+	type yamlStruct struct {
+		Bytes   ByteSlice[[]byte]
+		Strings Slice[string]
+		Structs SliceView[*testStruct, testStructView]
+		Tagged  Slice[tagged]
+		Map     Map[string, int]
+	}
+	in := yamlStruct{
+		Bytes:   ByteSliceOf([]byte{0, 1, 0xff, 'a'}),
+		Strings: SliceOf([]string{"a", "b"}),
+		Structs: SliceOfViews([]*testStruct{{1}, {2}}),
+		Tagged:  SliceOf([]tagged{{Name: "a", Weight: 0.5, Secret: "s"}, {Name: "b"}}),
+		Map:     MapOf(map[string]int{"x": 1, "y": 2}),
+	}
+	b, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `bytes: AAH/YQ==
+strings:
+    - a
+    - b
+structs:
+    - "N": 1
+    - "N": 2
+tagged:
+    - name: a
+      weight: 0.5
+    - name: b
+map:
+    x: 1
+    "y": 2
+`
+	if string(b) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b, want)
+	}
+
+	var out yamlStruct
+	if err := yaml.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Bytes.EqualView(in.Bytes) {
+		t.Errorf("Bytes = %v; want %v", out.Bytes.AsSlice(), in.Bytes.AsSlice())
+	}
+	if !SliceEqual(out.Strings, in.Strings) {
+		t.Errorf("Strings = %v; want %v", out.Strings, in.Strings)
+	}
+	if !SliceViewsEqual(out.Structs, in.Structs, func(a, b testStructView) bool { return a.ж.N == b.ж.N }) {
+		t.Errorf("Structs did not round-trip: %v", out.Structs.AsSlice())
+	}
+	wantTagged := []tagged{{Name: "a", Weight: 0.5}, {Name: "b"}}
+	if got := out.Tagged.AsSlice(); !slices.Equal(got, wantTagged) {
+		t.Errorf("Tagged = %+v; want %+v", got, wantTagged)
+	}
+	if !maps.Equal(out.Map.AsMap(), in.Map.AsMap()) {
+		t.Errorf("Map = %v; want %v", out.Map.AsMap(), in.Map.AsMap())
+	}
+
+	// As with JSON, unmarshaling into an initialized view fails.
+	if err := yaml.Unmarshal([]byte("- c\n"), &in.Strings); err == nil {
+		t.Error("unmarshal into initialized Slice succeeded")
+	}
+	if got := in.Strings.AsSlice(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("initialized Slice modified to %q", got)
+	}
+}